@@ -1,45 +1,100 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/ypeckstadt/bump/internal/bump"
 	"github.com/ypeckstadt/bump/internal/config"
+	"github.com/ypeckstadt/bump/internal/git"
 	"github.com/ypeckstadt/bump/pkg/version"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	cfg *config.Config
+	cfg        *config.Config
+	configFile string
+	// cancelTimeout releases the context created for the global timeout
+	cancelTimeout context.CancelFunc = func() {}
 )
 
 func main() {
 	cfg = config.New()
 
+	// Cancel in-flight git commands and checks on Ctrl-C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		// Restore default handling so a second Ctrl-C exits immediately
+		stop()
+	}()
+
 	rootCmd := &cobra.Command{
 		Use:   "bump",
 		Short: "A version bumping tool for semantic versioning",
 		Long: `Bump is a CLI tool for managing semantic versions in git repositories.
 It provides both interactive and quick release modes with git integration.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return loadConfig(cmd)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) == 0 {
-				runInteractiveMode()
+				runInteractiveMode(cmd.Context())
 			} else {
-				runQuickMode(args[0])
+				runQuickMode(cmd.Context(), args[0])
 			}
 		},
 	}
 
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Configuration file (default: .bump.yaml, .bump.yml or .bump.toml in the repository root)")
 	rootCmd.PersistentFlags().BoolVar(&cfg.DryRun, "dry-run", false, "Show what would happen without making changes")
 	rootCmd.PersistentFlags().BoolVar(&cfg.Verbose, "verbose", false, "Enable verbose output")
 	rootCmd.PersistentFlags().BoolVar(&cfg.NoBranch, "nobranch", false, "Skip branch creation prompt entirely")
 	rootCmd.PersistentFlags().BoolVar(&cfg.CreateBranch, "create-branch", false, "Create a branch for the tag")
 	rootCmd.PersistentFlags().StringVar(&cfg.SourceBranch, "source-branch", "", "Source branch for creating the new branch (default: main/master)")
-	rootCmd.PersistentFlags().StringVar(&cfg.BranchName, "branch-name", "", "Name for the new branch (default: tag name without 'v' prefix)")
+	rootCmd.PersistentFlags().StringVar(&cfg.BranchName, "branch-name", "", "Name for the new branch (default: tag name without its prefix)")
 	rootCmd.PersistentFlags().BoolVar(&cfg.AutoMerge, "auto-merge", false, "Automatically merge if branch exists")
 	rootCmd.PersistentFlags().BoolVar(&cfg.AutoPush, "auto-push", false, "Automatically push the branch")
+	rootCmd.PersistentFlags().StringVar(&cfg.TagScope, "tag-scope", config.TagScopeReachable, "Tags considered for the current version: reachable (from HEAD) or all")
+	rootCmd.PersistentFlags().StringVar(&cfg.TagPrefix, "tag-prefix", "v", "Prefix of version tags")
+	rootCmd.PersistentFlags().StringVar(&cfg.TagFormat, "tag-format", "", "Format of version tags with a {version} placeholder, e.g. release-{version}-final (overrides --tag-prefix)")
+	rootCmd.PersistentFlags().StringVar(&cfg.Scheme, "scheme", config.SchemeSemVer, "Versioning scheme (semver or calver)")
+	rootCmd.PersistentFlags().StringVar(&cfg.CalVerFormat, "calver-format", "YYYY.0M.MICRO", "Format of calendar versions, e.g. YY.0M.MICRO (with --scheme calver)")
+	rootCmd.PersistentFlags().StringVar(&cfg.Path, "path", "", "Module directory in a monorepo, relative to the repository root; its tags look like path/v1.2.3 (alias --module)")
+	rootCmd.PersistentFlags().StringVar(&cfg.Remote, "remote", "origin", "Remote to push tags and branches to")
+	rootCmd.PersistentFlags().BoolVar(&cfg.SkipChecks, "skip-checks", false, "Skip pre-release checks")
+	rootCmd.PersistentFlags().BoolVar(&cfg.QuickChecks, "quick-checks", false, "Run pre-release checks in quick mode too")
+	rootCmd.PersistentFlags().BoolVar(&cfg.BuiltinChecks, "builtin-checks", true, "Run the checks of detected project types in addition to declared checks")
+	rootCmd.PersistentFlags().DurationVar(&cfg.Timeout, "timeout", 0, "Abort the whole run after this duration (e.g. 10m)")
+	rootCmd.PersistentFlags().DurationVar(&cfg.CheckTimeout, "check-timeout", 0, "Fail any pre-release check that runs longer than this (e.g. 5m)")
+	rootCmd.PersistentFlags().IntVar(&cfg.CheckWorkers, "check-workers", 1, "Number of pre-release checks to run concurrently")
+	rootCmd.PersistentFlags().StringSliceVar(&cfg.AllowFailingChecks, "allow-failing-checks", nil, "Checks whose failure does not block the release (e.g. lint,test)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.APICheck, "api-check", true, "Refuse minor and patch releases of Go modules with incompatible API changes")
+	rootCmd.PersistentFlags().StringVar(&cfg.GoModulePath, "go-module-path", config.ModulePathBlock, "When a major release needs a new Go module path (/v2): block, rewrite or ignore")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Changelog, "changelog", false, "Prepend the release to the changelog before tagging")
+	rootCmd.PersistentFlags().StringVar(&cfg.ChangelogFile, "changelog-file", "CHANGELOG.md", "Changelog file to update")
+	rootCmd.PersistentFlags().BoolVar(&cfg.CommitChangelog, "commit-changelog", false, "Commit the changelog update so the tag includes it")
+	rootCmd.PersistentFlags().StringVar(&cfg.ReleaseCommitMessage, "release-commit-message", "chore(release): {{.NewVersion}}", "Template of the message of the release commit")
+	rootCmd.PersistentFlags().BoolVar(&cfg.SignCommits, "sign-commits", false, "Sign the commits bump creates (git commit -S)")
+	rootCmd.PersistentFlags().StringVar(&cfg.TagMessageTemplate, "tag-message-template", "", "Go text/template for the tag annotation (default \"Release {{.NewVersion}}\")")
+	rootCmd.PersistentFlags().StringVar(&cfg.TagMessageTemplateFile, "tag-message-template-file", "", "File containing the tag annotation template")
+	rootCmd.PersistentFlags().StringVar(&cfg.Preid, "preid", "", "Pre-release identifier for prerelease bumps (e.g. alpha, beta, rc; default: the current channel, or rc for a new pre-release)")
+
+	// Accept --module as an alias of --path
+	rootCmd.SetGlobalNormalizationFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "module" {
+			name = "path"
+		}
+		return pflag.NormalizedName(name)
+	})
 
 	// Add standard --version flag for CI compatibility
 	var showVersion bool
@@ -69,7 +124,7 @@ It provides both interactive and quick release modes with git integration.`,
 				fmt.Printf("Build Date: %s\n", buildInfo.BuildDate)
 				fmt.Printf("Go Version: %s\n", buildInfo.GoVersion)
 			} else if showRepo {
-				currentVersion := bump.GetCurrentVersion()
+				currentVersion := bump.GetCurrentVersion(cmd.Context(), cfg)
 				fmt.Printf("Repository version: %s\n", currentVersion)
 			} else {
 				// Show tool version by default (for CI compatibility)
@@ -82,11 +137,11 @@ It provides both interactive and quick release modes with git integration.`,
 	versionCmd.Flags().BoolVar(&showRepo, "repo", false, "Show current repository version")
 
 	quickCmd := &cobra.Command{
-		Use:   "quick [patch|minor|major]",
+		Use:   "quick [patch|minor|major|prepatch|preminor|premajor|prerelease|release|auto]",
 		Short: "Quick release without prompts",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			runQuickMode(args[0])
+			runQuickMode(cmd.Context(), args[0])
 		},
 	}
 
@@ -94,7 +149,43 @@ It provides both interactive and quick release modes with git integration.`,
 		Use:   "interactive",
 		Short: "Interactive release with prompts and checks",
 		Run: func(cmd *cobra.Command, args []string) {
-			runInteractiveMode()
+			runInteractiveMode(cmd.Context())
+		},
+	}
+
+	changelogCmd := &cobra.Command{
+		Use:   "changelog [patch|minor|major|prepatch|preminor|premajor|prerelease|release|auto]",
+		Short: "Prepend the next release to the changelog without tagging",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			versionType := "auto"
+			if len(args) == 1 {
+				versionType = args[0]
+			}
+			release := bump.NewRelease(cmd.Context(), cfg)
+			exitOnError(cmd.Context(), release.GenerateChangelog(cmd.Context(), versionType))
+		},
+	}
+
+	var initYes bool
+	var initForce bool
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a .bump.yaml configuration for the repository",
+		Run: func(cmd *cobra.Command, args []string) {
+			initializer := bump.NewInitializer(cfg)
+			exitOnError(cmd.Context(), initializer.Run(cmd.Context(), initYes, initForce))
+		},
+	}
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "Write detected defaults without prompting")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite an existing configuration file")
+
+	nextCmd := &cobra.Command{
+		Use:   "next",
+		Short: "Show the next version suggested by conventional commits",
+		Run: func(cmd *cobra.Command, args []string) {
+			release := bump.NewRelease(cmd.Context(), cfg)
+			exitOnError(cmd.Context(), release.ShowNext(cmd.Context()))
 		},
 	}
 
@@ -102,41 +193,114 @@ It provides both interactive and quick release modes with git integration.`,
 		Use:   "status",
 		Short: "Show current repository version and status",
 		Run: func(cmd *cobra.Command, args []string) {
-			currentVersion := bump.GetCurrentVersion()
-			fmt.Printf("Current repository version: %s\n", currentVersion)
+			exitOnError(cmd.Context(), bump.ShowStatus(cmd.Context(), cfg))
+		},
+	}
+
+	resumeCmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Continue a release that stopped part way from its first incomplete step",
+		Run: func(cmd *cobra.Command, args []string) {
+			release := bump.NewRelease(cmd.Context(), cfg)
+			exitOnError(cmd.Context(), release.Resume(cmd.Context()))
+		},
+	}
+
+	abortCmd := &cobra.Command{
+		Use:   "abort",
+		Short: "Undo the local changes of a release that stopped part way",
+		Run: func(cmd *cobra.Command, args []string) {
+			release := bump.NewRelease(cmd.Context(), cfg)
+			exitOnError(cmd.Context(), release.Abort(cmd.Context()))
 		},
 	}
 
+	var tagsSort string
 	tagsCmd := &cobra.Command{
 		Use:   "tags",
-		Short: "List all tags sorted by creation date (newest first)",
+		Short: "List all tags sorted by creation date or version (newest first)",
 		Run: func(cmd *cobra.Command, args []string) {
-			release := bump.NewRelease(cfg)
-			if err := release.ListTags(); err != nil {
-				log.Fatal(err)
-			}
+			release := bump.NewRelease(cmd.Context(), cfg)
+			exitOnError(cmd.Context(), release.ListTags(cmd.Context(), tagsSort))
 		},
 	}
+	tagsCmd.Flags().StringVar(&tagsSort, "sort", "date", "Sort order: date or version")
 
-	rootCmd.AddCommand(versionCmd, quickCmd, interactiveCmd, statusCmd, tagsCmd)
+	rootCmd.AddCommand(versionCmd, initCmd, quickCmd, interactiveCmd, nextCmd, changelogCmd, statusCmd, tagsCmd, resumeCmd, abortCmd)
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.ExecuteContext(ctx)
+	cancelTimeout()
+	if err != nil {
 		log.Fatal(err)
 	}
 }
 
-func runInteractiveMode() {
-	fmt.Println("Starting interactive release mode...")
-	release := bump.NewRelease(cfg)
-	if err := release.RunInteractive(); err != nil {
-		log.Fatal(err)
+// loadConfig layers the user and repository configuration files and BUMP_*
+// environment variables under the flags given on the command line.
+func loadConfig(cmd *cobra.Command) error {
+	// Flags parsed fine, so further errors are not usage errors
+	cmd.SilenceUsage = true
+
+	repoDir := "."
+	if root, err := git.NewClient(cfg).GetRepoRoot(cmd.Context()); err == nil {
+		repoDir = root
+	}
+
+	loader := &config.Loader{
+		RepoDir: repoDir,
+		File:    configFile,
+		IsSet: func(key string) bool {
+			return cmd.Flags().Changed(config.FlagName(key))
+		},
+	}
+	if err := loader.Load(cfg); err != nil {
+		return err
+	}
+
+	if cfg.Verbose {
+		for _, path := range loader.Loaded {
+			fmt.Printf("Loaded configuration from %s\n", path)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
 	}
+
+	if cfg.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+		cmd.SetContext(ctx)
+		cancelTimeout = cancel
+	}
+
+	return nil
 }
 
-func runQuickMode(versionType string) {
-	fmt.Printf("Running quick %s release...\n", versionType)
-	release := bump.NewRelease(cfg)
-	if err := release.RunQuick(versionType); err != nil {
+// exitOnError exits with err, if any, explaining when the run was
+// interrupted or timed out.
+func exitOnError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		log.Fatalf("Aborted after the %s timeout: %v", cfg.Timeout, err)
+	case ctx.Err() != nil:
+		log.Fatalf("Aborted: %v", err)
+	default:
 		log.Fatal(err)
 	}
 }
+
+func runInteractiveMode(ctx context.Context) {
+	fmt.Println("Starting interactive release mode...")
+	release := bump.NewRelease(ctx, cfg)
+	exitOnError(ctx, release.RunInteractive(ctx))
+}
+
+func runQuickMode(ctx context.Context, versionType string) {
+	fmt.Printf("Running quick %s release...\n", versionType)
+	release := bump.NewRelease(ctx, cfg)
+	exitOnError(ctx, release.RunQuick(ctx, versionType))
+}
@@ -0,0 +1,85 @@
+// Package apicompat compares the exported API of two versions of a Go
+// module.
+package apicompat
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/apidiff"
+	"golang.org/x/tools/go/packages"
+)
+
+// Report lists the API changes between two versions of a module.
+type Report struct {
+	Incompatible []string
+	Compatible   []string
+}
+
+// Compare loads the Go module in oldDir and the one in newDir and reports
+// the changes to their exported API. Internal packages and commands are not
+// part of the API and are ignored.
+func Compare(ctx context.Context, oldDir, newDir string) (*Report, error) {
+	oldModule, err := loadModule(ctx, oldDir)
+	if err != nil {
+		return nil, err
+	}
+	newModule, err := loadModule(ctx, newDir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	for _, change := range apidiff.ModuleChanges(oldModule, newModule).Changes {
+		if change.Compatible {
+			report.Compatible = append(report.Compatible, change.Message)
+		} else {
+			report.Incompatible = append(report.Incompatible, change.Message)
+		}
+	}
+	sort.Strings(report.Compatible)
+	sort.Strings(report.Incompatible)
+
+	return report, nil
+}
+
+func loadModule(ctx context.Context, dir string) (*apidiff.Module, error) {
+	cfg := &packages.Config{
+		Context: ctx,
+		Dir:     dir,
+		Mode:    packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps | packages.NeedModule,
+		// Compare the module on its own, not as part of a workspace
+		Env: append(os.Environ(), "GOWORK=off"),
+	}
+	loaded, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages in %s: %w", dir, err)
+	}
+	if len(loaded) == 0 || loaded[0].Module == nil {
+		return nil, fmt.Errorf("found no Go packages in %s", dir)
+	}
+
+	module := &apidiff.Module{Path: loaded[0].Module.Path}
+	for _, pkg := range loaded {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("failed to load %s: %v", pkg.PkgPath, pkg.Errors[0])
+		}
+		if pkg.Name == "main" || isInternal(strings.TrimPrefix(pkg.PkgPath, module.Path)) {
+			continue
+		}
+		module.Packages = append(module.Packages, pkg.Types)
+	}
+
+	return module, nil
+}
+
+// isInternal reports whether the module-relative package path is an
+// internal package, which other modules cannot import.
+func isInternal(path string) bool {
+	path = strings.Trim(path, "/")
+	return path == "internal" || strings.HasPrefix(path, "internal/") ||
+		strings.HasSuffix(path, "/internal") || strings.Contains(path, "/internal/")
+}
@@ -0,0 +1,160 @@
+package bump
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ypeckstadt/bump/internal/apicompat"
+	"github.com/ypeckstadt/bump/internal/config"
+	"github.com/ypeckstadt/bump/internal/version"
+
+	"github.com/manifoldco/promptui"
+)
+
+// checkAPICompatibility compares the exported API of the Go module in the
+// repository root, or the selected module, at the current version with HEAD
+// when versionType is too small a bump for incompatible changes. If there
+// are any, it returns the bump type that allows them: right away when the
+// type was suggested by the commits (auto), after asking in interactive
+// mode, and otherwise it refuses the release. Without a module, a previous
+// version, under CalVer or with the check disabled, versionType is returned
+// unchanged.
+func (r *Release) checkAPICompatibility(ctx context.Context, versionType string, auto, interactive bool) (string, error) {
+	if !r.cfg.APICheck || r.cfg.Scheme != config.SchemeSemVer || r.version.Raw == "" {
+		return versionType, nil
+	}
+
+	newVersion, err := r.version.Bump(versionType, r.cfg.Preid)
+	if err != nil {
+		return "", err
+	}
+	if allowsBreakingChanges(r.version, newVersion) {
+		return versionType, nil
+	}
+
+	dir, err := r.moduleRoot(ctx)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+		return versionType, nil
+	}
+
+	printInfo(fmt.Sprintf("Comparing the exported API with %s...", r.version.Raw))
+	report, err := r.compareAPI(ctx, r.version.Raw)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", err
+		}
+		printWarning(fmt.Sprintf("⚠️  Could not compare the API: %v", err))
+		return versionType, nil
+	}
+
+	if len(report.Incompatible) == 0 {
+		printSuccess(fmt.Sprintf("✅ No incompatible API changes since %s", r.version.Raw))
+		return versionType, nil
+	}
+
+	printWarning(fmt.Sprintf("⚠️  %d incompatible API change(s) since %s:", len(report.Incompatible), r.version.Raw))
+	for i, change := range report.Incompatible {
+		if i >= 10 {
+			fmt.Printf("  ... and %d more\n", len(report.Incompatible)-10)
+			break
+		}
+		fmt.Printf("  %s\n", change)
+	}
+
+	required := requiredBump(r.version, versionType)
+	requiredVersion, err := r.version.Bump(required, r.cfg.Preid)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case auto:
+		printInfo(fmt.Sprintf("Suggesting %s (%s) because of incompatible API changes", required, r.tagName(requiredVersion)))
+		return required, nil
+	case interactive:
+		return r.promptAPIBump(versionType, newVersion, required, requiredVersion)
+	default:
+		return "", fmt.Errorf("incompatible API changes require a %s release (%s) instead of %s (%s); use --api-check=false to release anyway",
+			required, r.tagName(requiredVersion), versionType, r.tagName(newVersion))
+	}
+}
+
+// compareAPI exports ref and HEAD to temporary directories and compares
+// the exported API of the Go module in their roots, or in the directory of
+// the selected module.
+func (r *Release) compareAPI(ctx context.Context, ref string) (*apicompat.Report, error) {
+	tmp, err := os.MkdirTemp("", "bump-api-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	oldDir := filepath.Join(tmp, "old")
+	newDir := filepath.Join(tmp, "new")
+	if err := r.git.ExportTree(ctx, ref, oldDir); err != nil {
+		return nil, err
+	}
+	if err := r.git.ExportTree(ctx, "HEAD", newDir); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.FromSlash(r.cfg.ModuleDir())
+	return apicompat.Compare(ctx, filepath.Join(oldDir, dir), filepath.Join(newDir, dir))
+}
+
+func (r *Release) promptAPIBump(versionType string, newVersion *version.Version, required string, requiredVersion *version.Version) (string, error) {
+	prompt := promptui.Select{
+		Label: "Incompatible API changes found",
+		Items: []string{
+			fmt.Sprintf("Release as %s (%s) [suggested]", required, r.tagName(requiredVersion)),
+			fmt.Sprintf("Continue with %s (%s)", versionType, r.tagName(newVersion)),
+			"Cancel release",
+		},
+	}
+
+	index, _, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+
+	switch index {
+	case 0:
+		return required, nil
+	case 1:
+		return versionType, nil
+	default:
+		return "", fmt.Errorf("release cancelled")
+	}
+}
+
+// allowsBreakingChanges reports whether going from old to new may break
+// the API: a new major version, a new minor version before 1.0.0, or the
+// next pre-release or final release of such a version.
+func allowsBreakingChanges(old, new *version.Version) bool {
+	if new.Major > old.Major || (new.Major == 0 && new.Minor > old.Minor) {
+		return true
+	}
+
+	sameCore := old.Major == new.Major && old.Minor == new.Minor && old.Patch == new.Patch
+	breakingLine := new.Patch == 0 && (new.Major == 0 || new.Minor == 0)
+	return old.IsPrerelease() && sameCore && breakingLine
+}
+
+// requiredBump returns the smallest bump from current that allows breaking
+// changes, as a pre-release when versionType is one.
+func requiredBump(current *version.Version, versionType string) string {
+	bump := "major"
+	if current.Major == 0 {
+		bump = "minor"
+	}
+	if strings.HasPrefix(versionType, "pre") {
+		return "pre" + bump
+	}
+	return bump
+}
@@ -1,14 +1,74 @@
 package bump
 
 import (
-	"github.com/ypeckstadt/bump/internal/config"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"os"
 	"os/exec"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ypeckstadt/bump/internal/command"
+	"github.com/ypeckstadt/bump/internal/config"
+)
+
+const (
+	CheckPassed  = "pass"
+	CheckFailed  = "fail"
+	CheckSkipped = "skip"
 )
 
+// skipError marks a check that did not run, such as a linter that is not
+// installed.
+type skipError struct {
+	reason string
+}
+
+func (e *skipError) Error() string {
+	return e.reason
+}
+
+func skipCheck(reason string) error {
+	return &skipError{reason: reason}
+}
+
+// CheckResult is the outcome of a single pre-release check.
+type CheckResult struct {
+	ID       string
+	Name     string
+	Status   string
+	Allowed  bool
+	Err      error
+	Output   string
+	Duration time.Duration
+}
+
+// check is a pre-release check ready to run. fn writes the output of the
+// commands it runs to out.
+type check struct {
+	id        string
+	name      string
+	order     int
+	advisory  bool
+	dependsOn []string
+	// timeout overrides the configured check timeout when set
+	timeout time.Duration
+	fn      func(ctx context.Context, out io.Writer) error
+}
+
 type Checker struct {
-	cfg *config.Config
+	cfg     *config.Config
+	results []CheckResult
+	// outputMu serialises streamed output of concurrently running checks
+	outputMu sync.Mutex
 }
 
 func NewChecker(cfg *config.Config) *Checker {
@@ -17,95 +77,455 @@ func NewChecker(cfg *config.Config) *Checker {
 	}
 }
 
-func (c *Checker) RunAll() error {
-	checks := []struct {
-		name string
-		fn   func() error
-	}{
-		{"Build", c.checkBuild},
-		{"Tests", c.checkTests},
-		{"Lint", c.checkLint},
-		{"Go mod tidy", c.checkGoModTidy},
+// RunAll runs every check, prints a summary and returns an error naming the
+// failed checks. Up to CheckWorkers checks run at the same time, each once
+// all of its dependencies have finished. The first failure of a required
+// check cancels the remaining checks, as does cancelling ctx. Failures of
+// advisory checks and of checks listed in AllowFailingChecks are reported
+// but do not fail the run.
+func (c *Checker) RunAll(ctx context.Context) error {
+	checks, err := c.checks()
+	if err != nil {
+		return err
+	}
+	if err := c.validateAllowedToFail(checks); err != nil {
+		return err
+	}
+	if len(checks) == 0 {
+		if c.cfg.BuiltinChecks {
+			printWarning("No supported project type detected and no checks declared")
+		} else {
+			printInfo("No checks to run")
+		}
+		return nil
+	}
+
+	c.results = c.schedule(ctx, checks)
+	c.printSummary()
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("checks aborted: %w", ctx.Err())
 	}
 
-	for _, check := range checks {
-		if c.cfg.Verbose {
-			printInfo(fmt.Sprintf("Running %s check...", check.name))
+	var failed []string
+	for _, result := range c.results {
+		if result.Status != CheckFailed {
+			continue
+		}
+		printError(fmt.Sprintf("%s check failed: %v", result.Name, result.Err))
+		if result.Output != "" && !c.cfg.Verbose {
+			fmt.Println(result.Output)
+		}
+		if !result.Allowed {
+			failed = append(failed, result.ID)
 		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d check(s) failed: %s (use --allow-failing-checks or --skip-checks to override)", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// schedule runs checks respecting dependencies and the worker limit, and
+// returns their results in check order.
+func (c *Checker) schedule(ctx context.Context, checks []check) []CheckResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := c.cfg.CheckWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make(map[string]*CheckResult)
+	// notPassed holds checks that failed or did not run because of a
+	// failure, so their dependents are skipped
+	notPassed := make(map[string]bool)
+	done := make(chan CheckResult)
+	pending := checks
+	running := 0
+	cancelledBy := ""
 
-		if err := check.fn(); err != nil {
-			if c.cfg.Verbose {
-				printError(fmt.Sprintf("❌ %s check failed: %v", check.name, err))
+	for len(pending) > 0 || running > 0 {
+		// Skip or start pending checks, in order, until nothing changes
+		for changed := true; changed; {
+			changed = false
+			var waiting []check
+			for _, check := range pending {
+				if reason := skipReason(check, notPassed, cancelledBy); reason != "" {
+					results[check.id] = skippedResult(check, reason)
+					notPassed[check.id] = true
+					changed = true
+					continue
+				}
+				if running < workers && dependenciesFinished(check, results) {
+					running++
+					go func() {
+						done <- c.run(ctx, check)
+					}()
+					continue
+				}
+				waiting = append(waiting, check)
 			}
-			continue
+			pending = waiting
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-done
+		running--
+		results[result.ID] = &result
+		if result.Status == CheckFailed {
+			notPassed[result.ID] = true
+		}
+
+		if result.Status == CheckFailed && !result.Allowed && cancelledBy == "" {
+			cancelledBy = result.ID
+			cancel()
+		}
+	}
+
+	ordered := make([]CheckResult, 0, len(checks))
+	for _, check := range checks {
+		if result, ok := results[check.id]; ok {
+			ordered = append(ordered, *result)
+		}
+	}
+	return ordered
+}
+
+// skipReason explains why a pending check must be skipped, or returns an
+// empty string when it may still run.
+func skipReason(check check, notPassed map[string]bool, cancelledBy string) string {
+	if cancelledBy != "" {
+		return fmt.Sprintf("cancelled after %s failed", cancelledBy)
+	}
+	for _, dependency := range check.dependsOn {
+		if notPassed[dependency] {
+			return fmt.Sprintf("dependency %s did not pass", dependency)
+		}
+	}
+	return ""
+}
+
+func dependenciesFinished(check check, results map[string]*CheckResult) bool {
+	for _, dependency := range check.dependsOn {
+		if _, ok := results[dependency]; !ok {
+			return false
 		}
+	}
+	return true
+}
+
+// run runs a single check within its timeout, streaming its output with a
+// prefix in verbose mode.
+func (c *Checker) run(ctx context.Context, check check) CheckResult {
+	if c.cfg.Verbose {
+		printInfo(fmt.Sprintf("Running %s check...", check.name))
+	}
+
+	var output bytes.Buffer
+	var out io.Writer = &output
+	var stream *prefixWriter
+	if c.cfg.Verbose {
+		stream = &prefixWriter{mu: &c.outputMu, out: os.Stdout, prefix: fmt.Sprintf("[%s] ", check.id)}
+		out = io.MultiWriter(&output, stream)
+	}
+
+	timeout := c.cfg.CheckTimeout
+	if check.timeout > 0 {
+		timeout = check.timeout
+	}
+	checkCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		checkCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
-		if c.cfg.Verbose {
+	start := time.Now()
+	err := check.fn(checkCtx, out)
+	if stream != nil {
+		stream.Flush()
+	}
+
+	result := CheckResult{
+		ID:       check.id,
+		Name:     check.name,
+		Status:   CheckPassed,
+		Allowed:  check.advisory || c.isAllowedToFail(check.id),
+		Err:      err,
+		Output:   strings.TrimSpace(output.String()),
+		Duration: time.Since(start),
+	}
+
+	var skip *skipError
+	switch {
+	case errors.As(err, &skip):
+		result.Status = CheckSkipped
+	case err != nil && ctx.Err() != nil:
+		result.Status = CheckSkipped
+		result.Err = skipCheck("cancelled")
+	case err != nil && checkCtx.Err() == context.DeadlineExceeded:
+		result.Status = CheckFailed
+		result.Err = fmt.Errorf("timed out after %s", timeout)
+	case err != nil:
+		result.Status = CheckFailed
+	}
+
+	if c.cfg.Verbose {
+		switch result.Status {
+		case CheckPassed:
 			printSuccess(fmt.Sprintf("✅ %s check passed", check.name))
+		case CheckSkipped:
+			printWarning(fmt.Sprintf("⏭️  %s check skipped: %v", check.name, result.Err))
+		default:
+			printError(fmt.Sprintf("❌ %s check failed", check.name))
 		}
 	}
 
-	return nil
+	return result
 }
 
-func (c *Checker) checkBuild() error {
-	if c.cfg.DryRun {
-		printInfo("[DRY RUN] Would run: go build")
-		return nil
+func skippedResult(check check, reason string) *CheckResult {
+	return &CheckResult{
+		ID:     check.id,
+		Name:   check.name,
+		Status: CheckSkipped,
+		Err:    skipCheck(reason),
 	}
+}
 
-	cmd := exec.Command("go", "build", "./...")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("build failed: %s", string(output))
+// checks returns the built-in checks of every detected project, unless
+// disabled, together with the declared checks, sorted by order. A declared
+// check replaces the built-in check with the same name. It fails on unknown
+// or circular dependencies.
+func (c *Checker) checks() ([]check, error) {
+	var checks []check
+	builtinOrder := make(map[string]int)
+	if c.cfg.BuiltinChecks {
+		projects, err := detectProjects(".")
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			for _, check := range p.ecosystem.checks(c, p) {
+				check.order = 10 * (len(checks) + 1)
+				checks = append(checks, check)
+				builtinOrder[check.id] = check.order
+			}
+		}
 	}
-	return nil
+
+	for _, checkConfig := range c.cfg.Checks {
+		declared := c.commandCheck(checkConfig)
+		if order, ok := builtinOrder[declared.id]; ok {
+			// Replace the built-in check, keeping its position by default
+			if checkConfig.Order == 0 {
+				declared.order = order
+			}
+			for i := range checks {
+				if checks[i].id == declared.id {
+					checks[i] = declared
+				}
+			}
+			continue
+		}
+		checks = append(checks, declared)
+	}
+
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].order < checks[j].order
+	})
+
+	if err := validateDependencies(checks); err != nil {
+		return nil, err
+	}
+	return checks, nil
 }
 
-func (c *Checker) checkTests() error {
-	if c.cfg.DryRun {
-		printInfo("[DRY RUN] Would run: go test")
-		return nil
+// validateDependencies rejects dependencies on unknown checks and
+// dependency cycles.
+func validateDependencies(checks []check) error {
+	byID := make(map[string]check)
+	for _, check := range checks {
+		byID[check.id] = check
 	}
 
-	cmd := exec.Command("go", "test", "./...")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		if strings.Contains(string(output), "no test files") {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+
+	var visit func(id string, path []string) error
+	visit = func(id string, path []string) error {
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("circular check dependency: %s", strings.Join(append(path, id), " → "))
+		case visited:
 			return nil
 		}
-		return fmt.Errorf("tests failed: %s", string(output))
+
+		state[id] = visiting
+		for _, dependency := range byID[id].dependsOn {
+			if _, ok := byID[dependency]; !ok {
+				return fmt.Errorf("check %s depends on unknown check %s", id, dependency)
+			}
+			if err := visit(dependency, append(path, id)); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		return nil
+	}
+
+	for _, check := range checks {
+		if err := visit(check.id, nil); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func (c *Checker) checkLint() error {
+// commandCheck turns a declared check into a check that runs its command
+// through the shell.
+func (c *Checker) commandCheck(checkConfig config.CheckConfig) check {
+	order := checkConfig.Order
+	if order == 0 {
+		order = math.MaxInt
+	}
+
+	// Timeouts were validated with the configuration
+	timeout, _ := checkConfig.TimeoutDuration()
+
+	return check{
+		id:        checkConfig.Name,
+		name:      checkConfig.Name,
+		order:     order,
+		advisory:  checkConfig.Severity == config.SeverityAdvisory,
+		dependsOn: checkConfig.DependsOn,
+		timeout:   timeout,
+		fn: func(ctx context.Context, out io.Writer) error {
+			return c.runCommandCheck(ctx, out, checkConfig)
+		},
+	}
+}
+
+func (c *Checker) runCommandCheck(ctx context.Context, out io.Writer, checkConfig config.CheckConfig) error {
 	if c.cfg.DryRun {
-		printInfo("[DRY RUN] Would run: golangci-lint run")
-		return nil
+		printInfo(fmt.Sprintf("[DRY RUN] Would run: %s", checkConfig.Run))
+		return skipCheck("dry run")
 	}
 
-	cmd := exec.Command("golangci-lint", "run")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		if strings.Contains(err.Error(), "executable file not found") {
-			return nil
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	cmd := command.NewGroup(ctx, shell, flag, checkConfig.Run) // #nosec G204 -- checks are declared by the user
+	cmd.Dir = checkConfig.Dir
+	cmd.Env = os.Environ()
+	for key, value := range checkConfig.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	return runCommand(cmd, out)
+}
+
+// runCommand runs cmd with its combined output sent to out.
+func runCommand(cmd *exec.Cmd, out io.Writer) error {
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// prefixWriter writes complete lines to out, each preceded by prefix, so
+// output of concurrent checks stays readable.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
 		}
-		return fmt.Errorf("lint failed: %s", string(output))
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
 	}
-	return nil
+	return len(p), nil
 }
 
-func (c *Checker) checkGoModTidy() error {
-	if c.cfg.DryRun {
-		printInfo("[DRY RUN] Would run: go mod tidy")
+// Flush writes a trailing partial line.
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+}
+
+// validateAllowedToFail fails when AllowFailingChecks names checks that
+// do not exist, so a typo does not leave a check blocking the release.
+func (c *Checker) validateAllowedToFail(checks []check) error {
+	known := make(map[string]bool, len(checks))
+	ids := make([]string, 0, len(checks))
+	for _, check := range checks {
+		known[check.id] = true
+		ids = append(ids, check.id)
+	}
+
+	var unknown []string
+	for _, id := range c.cfg.AllowFailingChecks {
+		if !known[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) == 0 {
 		return nil
 	}
 
-	cmd := exec.Command("go", "mod", "tidy")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("go mod tidy failed: %s", string(output))
+	if len(ids) == 0 {
+		return fmt.Errorf("unknown check(s) in allow_failing_checks: %s (no checks to run)", strings.Join(unknown, ", "))
 	}
-	return nil
+	return fmt.Errorf("unknown check(s) in allow_failing_checks: %s (known checks: %s)", strings.Join(unknown, ", "), strings.Join(ids, ", "))
+}
+
+func (c *Checker) isAllowedToFail(id string) bool {
+	for _, allowed := range c.cfg.AllowFailingChecks {
+		if allowed == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checker) printSummary() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tSTATUS\tDURATION\tNOTE")
+	for _, result := range c.results {
+		note := ""
+		switch {
+		case result.Status == CheckSkipped:
+			note = result.Err.Error()
+		case result.Status == CheckFailed && result.Allowed:
+			note = "allowed to fail"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.ID, result.Status, result.Duration.Round(time.Millisecond), note)
+	}
+	w.Flush()
 }
@@ -0,0 +1,326 @@
+package bump
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ypeckstadt/bump/internal/config"
+)
+
+// fakeCheck returns a check that records when it ran in log and returns
+// err.
+func fakeCheck(id string, log *runLog, err error, dependsOn ...string) check {
+	return check{
+		id:        id,
+		name:      id,
+		dependsOn: dependsOn,
+		fn: func(ctx context.Context, out io.Writer) error {
+			log.add(id)
+			return err
+		},
+	}
+}
+
+// runLog records the order in which checks ran.
+type runLog struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (l *runLog) add(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ids = append(l.ids, id)
+}
+
+func (l *runLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.ids, ",")
+}
+
+func newTestChecker(workers int) *Checker {
+	cfg := config.New()
+	cfg.CheckWorkers = workers
+	return NewChecker(cfg)
+}
+
+// statuses returns "id=status" for each result, in order.
+func statuses(results []CheckResult) string {
+	parts := make([]string, 0, len(results))
+	for _, result := range results {
+		parts = append(parts, result.ID+"="+result.Status)
+	}
+	return strings.Join(parts, ",")
+}
+
+func reasonOf(t *testing.T, results []CheckResult, id string) string {
+	t.Helper()
+	for _, result := range results {
+		if result.ID == id {
+			if result.Err == nil {
+				return ""
+			}
+			return result.Err.Error()
+		}
+	}
+	t.Fatalf("no result for %s", id)
+	return ""
+}
+
+func TestScheduleRunsDependenciesFirst(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		log := &runLog{}
+		checks := []check{
+			fakeCheck("test", log, nil, "build"),
+			fakeCheck("lint", log, nil, "test", "build"),
+			fakeCheck("build", log, nil),
+		}
+
+		results := newTestChecker(workers).schedule(context.Background(), checks)
+
+		if got, want := statuses(results), "test=pass,lint=pass,build=pass"; got != want {
+			t.Errorf("workers %d: results = %s, want %s in check order", workers, got, want)
+		}
+		if got, want := log.String(), "build,test,lint"; got != want {
+			t.Errorf("workers %d: ran %s, want %s", workers, got, want)
+		}
+	}
+}
+
+func TestScheduleWorkerLimit(t *testing.T) {
+	for _, workers := range []int{1, 2, 3} {
+		var running, most atomic.Int32
+		var checks []check
+		for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+			checks = append(checks, check{id: id, name: id, fn: func(ctx context.Context, out io.Writer) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					m := most.Load()
+					if n <= m || most.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				return nil
+			}})
+		}
+
+		results := newTestChecker(workers).schedule(context.Background(), checks)
+
+		if len(results) != len(checks) {
+			t.Fatalf("workers %d: %d results, want %d", workers, len(results), len(checks))
+		}
+		if got := most.Load(); got != int32(workers) {
+			t.Errorf("workers %d: at most %d checks ran at once, want %d", workers, got, workers)
+		}
+	}
+}
+
+func TestScheduleSkipsDependentsOfFailures(t *testing.T) {
+	log := &runLog{}
+	checker := newTestChecker(1)
+	checker.cfg.AllowFailingChecks = []string{"build"}
+	checks := []check{
+		fakeCheck("build", log, errors.New("exit status 1")),
+		fakeCheck("test", log, nil, "build"),
+		fakeCheck("e2e", log, nil, "test"),
+		fakeCheck("lint", log, nil),
+	}
+
+	results := checker.schedule(context.Background(), checks)
+
+	if got, want := statuses(results), "build=fail,test=skip,e2e=skip,lint=pass"; got != want {
+		t.Errorf("results = %s, want %s", got, want)
+	}
+	if got, want := log.String(), "build,lint"; got != want {
+		t.Errorf("ran %s, want %s", got, want)
+	}
+	if got, want := reasonOf(t, results, "test"), "dependency build did not pass"; got != want {
+		t.Errorf("test skipped because %q, want %q", got, want)
+	}
+	if got, want := reasonOf(t, results, "e2e"), "dependency test did not pass"; got != want {
+		t.Errorf("e2e skipped because %q, want %q", got, want)
+	}
+	if !results[0].Allowed {
+		t.Errorf("build failure is not marked as allowed")
+	}
+}
+
+func TestScheduleRunsDependentsOfSkippedChecks(t *testing.T) {
+	log := &runLog{}
+	checks := []check{
+		fakeCheck("lint", log, skipCheck("golangci-lint not installed")),
+		fakeCheck("lint-report", log, nil, "lint"),
+	}
+
+	results := newTestChecker(1).schedule(context.Background(), checks)
+
+	// Only failures skip dependents; a missing tool does not
+	if got, want := statuses(results), "lint=skip,lint-report=pass"; got != want {
+		t.Errorf("results = %s, want %s", got, want)
+	}
+	if got, want := reasonOf(t, results, "lint"), "golangci-lint not installed"; got != want {
+		t.Errorf("lint skipped because %q, want %q", got, want)
+	}
+}
+
+func TestScheduleCancelsAfterRequiredFailure(t *testing.T) {
+	log := &runLog{}
+	checks := []check{
+		fakeCheck("build", log, errors.New("exit status 2")),
+		fakeCheck("test", log, nil),
+		fakeCheck("lint", log, nil),
+	}
+
+	results := newTestChecker(1).schedule(context.Background(), checks)
+
+	if got, want := statuses(results), "build=fail,test=skip,lint=skip"; got != want {
+		t.Errorf("results = %s, want %s", got, want)
+	}
+	if got, want := log.String(), "build"; got != want {
+		t.Errorf("ran %s, want %s", got, want)
+	}
+	if got, want := reasonOf(t, results, "lint"), "cancelled after build failed"; got != want {
+		t.Errorf("lint skipped because %q, want %q", got, want)
+	}
+}
+
+func TestScheduleCancelsRunningChecks(t *testing.T) {
+	started := make(chan struct{})
+	checks := []check{
+		{id: "slow", name: "slow", fn: func(ctx context.Context, out io.Writer) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		{id: "build", name: "build", fn: func(ctx context.Context, out io.Writer) error {
+			<-started
+			return errors.New("exit status 1")
+		}},
+	}
+
+	results := newTestChecker(2).schedule(context.Background(), checks)
+
+	if got, want := statuses(results), "slow=skip,build=fail"; got != want {
+		t.Errorf("results = %s, want %s", got, want)
+	}
+	if got, want := reasonOf(t, results, "slow"), "cancelled"; got != want {
+		t.Errorf("slow skipped because %q, want %q", got, want)
+	}
+}
+
+func TestScheduleKeepsGoingAfterAllowedFailures(t *testing.T) {
+	log := &runLog{}
+	checker := newTestChecker(1)
+	checker.cfg.AllowFailingChecks = []string{"lint"}
+	advisory := fakeCheck("audit", log, errors.New("1 vulnerability"))
+	advisory.advisory = true
+	checks := []check{
+		fakeCheck("lint", log, errors.New("exit status 1")),
+		advisory,
+		fakeCheck("test", log, nil),
+	}
+
+	results := checker.schedule(context.Background(), checks)
+
+	if got, want := statuses(results), "lint=fail,audit=fail,test=pass"; got != want {
+		t.Errorf("results = %s, want %s", got, want)
+	}
+	for _, result := range results[:2] {
+		if !result.Allowed {
+			t.Errorf("%s failure is not marked as allowed", result.ID)
+		}
+	}
+}
+
+func TestScheduleTimeouts(t *testing.T) {
+	wait := func(ctx context.Context, out io.Writer) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	}
+
+	checker := newTestChecker(2)
+	checker.cfg.CheckTimeout = 20 * time.Millisecond
+	checker.cfg.AllowFailingChecks = []string{"default", "own"}
+	checks := []check{
+		{id: "default", name: "default", fn: wait},
+		{id: "own", name: "own", timeout: 30 * time.Millisecond, fn: wait},
+	}
+
+	results := checker.schedule(context.Background(), checks)
+
+	if got, want := statuses(results), "default=fail,own=fail"; got != want {
+		t.Errorf("results = %s, want %s", got, want)
+	}
+	if got, want := reasonOf(t, results, "default"), "timed out after 20ms"; got != want {
+		t.Errorf("default failed with %q, want %q", got, want)
+	}
+	if got, want := reasonOf(t, results, "own"), "timed out after 30ms"; got != want {
+		t.Errorf("own failed with %q, want %q", got, want)
+	}
+}
+
+func TestScheduleInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	waitForCancel := func(ctx context.Context, out io.Writer) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	checks := []check{
+		{id: "build", name: "build", fn: func(ctx context.Context, out io.Writer) error {
+			cancel()
+			return waitForCancel(ctx, out)
+		}},
+		{id: "test", name: "test", fn: waitForCancel},
+	}
+
+	results := newTestChecker(1).schedule(ctx, checks)
+
+	if got, want := statuses(results), "build=skip,test=skip"; got != want {
+		t.Errorf("results = %s, want %s", got, want)
+	}
+	if got, want := reasonOf(t, results, "build"), "cancelled"; got != want {
+		t.Errorf("build skipped because %q, want %q", got, want)
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	log := &runLog{}
+	tests := []struct {
+		name    string
+		checks  []check
+		wantErr string
+	}{
+		{name: "valid", checks: []check{fakeCheck("a", log, nil), fakeCheck("b", log, nil, "a")}},
+		{name: "unknown", checks: []check{fakeCheck("a", log, nil, "missing")}, wantErr: "check a depends on unknown check missing"},
+		{name: "self", checks: []check{fakeCheck("a", log, nil, "a")}, wantErr: "circular check dependency: a → a"},
+		{name: "cycle", checks: []check{fakeCheck("a", log, nil, "b"), fakeCheck("b", log, nil, "c"), fakeCheck("c", log, nil, "a")}, wantErr: "circular check dependency: a → b → c → a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDependencies(tt.checks)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateDependencies() failed: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("validateDependencies() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package bump
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ypeckstadt/bump/internal/version"
+)
+
+// releaseFiles collects the new content of the files a release changes, so
+// they are written and committed together in the release commit. Nothing
+// touches the working tree until the commit is made.
+type releaseFiles struct {
+	paths    []string
+	contents map[string][]byte
+}
+
+func newReleaseFiles() *releaseFiles {
+	return &releaseFiles{contents: make(map[string][]byte)}
+}
+
+// read returns the pending content of path, or its content on disk.
+func (f *releaseFiles) read(path string) ([]byte, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if content, ok := f.contents[path]; ok {
+		return content, nil
+	}
+	return os.ReadFile(path) // #nosec G304 -- files of the release
+}
+
+func (f *releaseFiles) set(path string, content []byte) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := f.contents[path]; !ok {
+		f.paths = append(f.paths, path)
+	}
+	f.contents[path] = content
+	return nil
+}
+
+// commitRelease writes the collected files and commits them with the
+// release commit message, signed when configured. In a dry run it prints
+// the diff of each file instead. It reports whether there was anything to
+// commit.
+func (r *Release) commitRelease(ctx context.Context, tx *transaction, files *releaseFiles, newVersion *version.Version, bumpType string) (bool, error) {
+	root, err := r.git.GetRepoRoot(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var paths, names []string
+	previous := make(map[string][]byte)
+	for _, path := range files.paths {
+		content := files.contents[path]
+		current, err := os.ReadFile(path) // #nosec G304 -- files of the release
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, err
+		}
+		if err == nil && string(current) == string(content) {
+			continue
+		}
+
+		name := path
+		if rel, err := filepath.Rel(root, path); err == nil {
+			name = filepath.ToSlash(rel)
+		}
+		if r.cfg.DryRun {
+			diff, err := r.git.Diff(ctx, name, current, content)
+			if err != nil {
+				return false, err
+			}
+			fmt.Print(diff)
+		}
+		paths = append(paths, path)
+		previous[path] = current
+		names = append(names, name)
+	}
+	if len(paths) == 0 {
+		return false, nil
+	}
+
+	message, err := renderTemplate("release commit message", r.cfg.ReleaseCommitMessage, r.buildMessageData(ctx, newVersion, bumpType))
+	if err != nil {
+		return false, err
+	}
+
+	if r.cfg.DryRun {
+		printInfo(fmt.Sprintf("[DRY RUN] Would commit %s with message: %s", strings.Join(names, ", "), message))
+		return true, nil
+	}
+
+	head, err := r.git.ResolveRef(ctx, "HEAD")
+	if err != nil {
+		return false, err
+	}
+	branch, err := r.git.GetCurrentBranch(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// Each change is recorded before it is made, so a release killed in
+	// between still undoes it; undoing a change that was not made does
+	// nothing
+	for _, path := range paths {
+		tx.record(change{Kind: changeFile, Ref: path, Content: previous[path]})
+		if err := writeReleaseFile(path, files.contents[path]); err != nil {
+			return false, err
+		}
+	}
+	tx.record(change{Kind: changeCommit, Ref: branch, Commit: head})
+	if err := r.git.CommitFiles(ctx, message, paths...); err != nil {
+		return false, err
+	}
+
+	printSuccess(fmt.Sprintf("✅ Created the release commit: %s", strings.Join(names, ", ")))
+	return true, nil
+}
+
+// writeReleaseFile writes content to path, keeping the permissions of an
+// existing file.
+func writeReleaseFile(path string, content []byte) error {
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, content, perm); err != nil { // #nosec G306 -- release files are meant to be readable
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
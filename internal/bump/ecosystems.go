@@ -0,0 +1,410 @@
+package bump
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ypeckstadt/bump/internal/command"
+)
+
+// ecosystem is a project type whose build, test and lint checks bump knows
+// how to run.
+type ecosystem struct {
+	name string
+	// manifests are the files that mark a project directory
+	manifests []string
+	// nested projects are checked on their own even below another project
+	// of the same type, since building the parent does not cover them
+	nested bool
+	checks func(c *Checker, p project) []check
+}
+
+// project is a directory containing the manifest of an ecosystem.
+type project struct {
+	ecosystem *ecosystem
+	// dir is relative to the directory bump runs in, "." for itself
+	dir string
+}
+
+var ecosystems = []*ecosystem{
+	{name: "Go", manifests: []string{"go.mod"}, nested: true, checks: goChecks},
+	{name: "Node", manifests: []string{"package.json"}, checks: nodeChecks},
+	{name: "Rust", manifests: []string{"Cargo.toml"}, checks: rustChecks},
+	{name: "Python", manifests: []string{"pyproject.toml"}, checks: pythonChecks},
+	{name: "Maven", manifests: []string{"pom.xml"}, checks: mavenChecks},
+	{name: "Gradle", manifests: []string{"build.gradle", "build.gradle.kts"}, checks: gradleChecks},
+}
+
+// ignoredDirs are never searched for projects.
+var ignoredDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"build":        true,
+	"dist":         true,
+	"testdata":     true,
+	"__pycache__":  true,
+	"venv":         true,
+}
+
+// detectProjects finds the projects in root and its subdirectories, parents
+// before children. Hidden directories and dependency or build output
+// directories are not searched.
+func detectProjects(root string) ([]project, error) {
+	var projects []project
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(entry.Name(), ".") || ignoredDirs[entry.Name()]) {
+			return filepath.SkipDir
+		}
+
+		dir, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		dir = filepath.ToSlash(dir)
+
+		for _, eco := range ecosystems {
+			if !hasManifest(path, eco) || (!eco.nested && insideProject(projects, eco, dir)) {
+				continue
+			}
+			projects = append(projects, project{ecosystem: eco, dir: dir})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect project types: %w", err)
+	}
+	return projects, nil
+}
+
+func hasManifest(path string, eco *ecosystem) bool {
+	for _, manifest := range eco.manifests {
+		if _, err := os.Stat(filepath.Join(path, manifest)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// insideProject reports whether dir lies below a project of eco.
+func insideProject(projects []project, eco *ecosystem, dir string) bool {
+	for _, p := range projects {
+		if p.ecosystem == eco && (p.dir == "." || strings.HasPrefix(dir, p.dir+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkID qualifies id with the project directory unless the project is
+// the directory bump runs in, so the root Go module keeps the plain build,
+// test, lint and go-mod-tidy names.
+func (p project) checkID(id string) string {
+	if p.dir == "." {
+		return id
+	}
+	return p.dir + ":" + id
+}
+
+func (p project) checkName(name string) string {
+	if p.dir == "." {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, p.dir)
+}
+
+// newCheck returns a check of p. Dependencies are checks of the same
+// project.
+func (p project) newCheck(id, name string, fn func(ctx context.Context, out io.Writer) error, dependsOn ...string) check {
+	for i, dependency := range dependsOn {
+		dependsOn[i] = p.checkID(dependency)
+	}
+	return check{
+		id:        p.checkID(id),
+		name:      p.checkName(name),
+		dependsOn: dependsOn,
+		fn:        fn,
+	}
+}
+
+// toolCommand returns a check function that runs name with args in dir. The
+// check is skipped when the tool is not installed.
+func (c *Checker) toolCommand(dir, name string, args ...string) func(ctx context.Context, out io.Writer) error {
+	return func(ctx context.Context, out io.Writer) error {
+		commandLine := strings.Join(append([]string{filepath.Base(name)}, args...), " ")
+		if c.cfg.DryRun {
+			printInfo(fmt.Sprintf("[DRY RUN] Would run in %s: %s", dir, commandLine))
+			return skipCheck("dry run")
+		}
+
+		if _, err := exec.LookPath(name); err != nil {
+			return skipCheck(fmt.Sprintf("%s not installed", filepath.Base(name)))
+		}
+
+		cmd := command.NewGroup(ctx, name, args...) // #nosec G204 -- fixed tools with fixed arguments
+		cmd.Dir = dir
+		return runCommand(cmd, out)
+	}
+}
+
+// wrapper returns the absolute path of a build tool wrapper script such as
+// gradlew in dir, or tool when there is none.
+func wrapper(dir, script, tool string) string {
+	if runtime.GOOS == "windows" {
+		script += map[string]string{"gradlew": ".bat", "mvnw": ".cmd"}[script]
+	}
+	path, err := filepath.Abs(filepath.Join(dir, script))
+	if err != nil {
+		return tool
+	}
+	if _, err := os.Stat(path); err != nil {
+		return tool
+	}
+	return path
+}
+
+func goChecks(c *Checker, p project) []check {
+	return []check{
+		p.newCheck("build", "Build", c.toolCommand(p.dir, "go", "build", "./...")),
+		p.newCheck("test", "Tests", c.toolCommand(p.dir, "go", "test", "./..."), "build"),
+		p.newCheck("lint", "Lint", c.toolCommand(p.dir, "golangci-lint", "run"), "build"),
+		p.newCheck("go-mod-tidy", "Go mod tidy", c.goModTidy(p.dir)),
+	}
+}
+
+// goModTidy returns a check function that verifies go.mod and go.sum in dir
+// are tidy, failing with the changes go mod tidy would make. It never
+// modifies them: it uses go mod tidy -diff, or tidies copies of the files on
+// toolchains older than Go 1.23.
+func (c *Checker) goModTidy(dir string) func(ctx context.Context, out io.Writer) error {
+	return func(ctx context.Context, out io.Writer) error {
+		if c.cfg.DryRun {
+			printInfo(fmt.Sprintf("[DRY RUN] Would run in %s: go mod tidy -diff", dir))
+			return skipCheck("dry run")
+		}
+
+		var output bytes.Buffer
+		cmd := command.NewGroup(ctx, "go", "mod", "tidy", "-diff")
+		cmd.Dir = dir
+		err := runCommand(cmd, &output)
+		if err != nil && strings.Contains(output.String(), "flag provided but not defined: -diff") {
+			return goModTidyCopy(ctx, dir, out)
+		}
+
+		if _, writeErr := out.Write(output.Bytes()); writeErr != nil {
+			return writeErr
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && ctx.Err() == nil && strings.HasPrefix(output.String(), "diff ") {
+			return errors.New("go.mod or go.sum is not tidy, run go mod tidy and commit the result")
+		}
+		return err
+	}
+}
+
+// goModTidyCopy runs go mod tidy on copies of go.mod and go.sum and writes
+// the lines it would remove and add to out.
+func goModTidyCopy(ctx context.Context, dir string, out io.Writer) error {
+	tmp, err := os.MkdirTemp("", "bump-tidy-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	files := []string{"go.mod", "go.sum"}
+	before := make(map[string][]byte)
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(dir, file)) // #nosec G304 -- path is built from a detected project directory
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		before[file] = data
+		if err := os.WriteFile(filepath.Join(tmp, file), data, 0o600); err != nil {
+			return err
+		}
+	}
+
+	cmd := command.NewGroup(ctx, "go", "mod", "tidy", "-modfile="+filepath.Join(tmp, "go.mod"))
+	cmd.Dir = dir
+	if err := runCommand(cmd, out); err != nil {
+		return err
+	}
+
+	tidy := true
+	for _, file := range files {
+		after, err := os.ReadFile(filepath.Join(tmp, file)) // #nosec G304 -- temporary copy
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(before[file], after) {
+			tidy = false
+			fmt.Fprint(out, lineDiff(file, string(before[file]), string(after)))
+		}
+	}
+
+	if !tidy {
+		return errors.New("go.mod or go.sum is not tidy, run go mod tidy and commit the result")
+	}
+	return nil
+}
+
+// lineDiff lists the lines only in before with a leading "-" and the lines
+// only in after with a leading "+". That is enough for go.mod and go.sum,
+// whose lines are largely independent of each other.
+func lineDiff(name, before, after string) string {
+	count := func(text string) map[string]int {
+		lines := make(map[string]int)
+		for _, line := range strings.Split(text, "\n") {
+			lines[line]++
+		}
+		return lines
+	}
+	beforeLines, afterLines := count(before), count(after)
+
+	var diff strings.Builder
+	fmt.Fprintf(&diff, "--- current/%s\n+++ tidy/%s\n", name, name)
+	for _, line := range strings.Split(before, "\n") {
+		if afterLines[line] > 0 {
+			afterLines[line]--
+			continue
+		}
+		fmt.Fprintf(&diff, "-%s\n", line)
+	}
+	afterLines = count(after)
+	for _, line := range strings.Split(after, "\n") {
+		if beforeLines[line] > 0 {
+			beforeLines[line]--
+			continue
+		}
+		fmt.Fprintf(&diff, "+%s\n", line)
+	}
+	return diff.String()
+}
+
+func nodeChecks(c *Checker, p project) []check {
+	manager := nodePackageManager(p.dir)
+	script := func(name string) func(ctx context.Context, out io.Writer) error {
+		return func(ctx context.Context, out io.Writer) error {
+			ok, err := hasNodeScript(p.dir, name)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return skipCheck(fmt.Sprintf("no %s script", name))
+			}
+			return c.toolCommand(p.dir, manager, "run", name)(ctx, out)
+		}
+	}
+
+	return []check{
+		p.newCheck("node-build", "Node build", script("build")),
+		p.newCheck("node-test", "Node tests", script("test"), "node-build"),
+		p.newCheck("node-lint", "Node lint", script("lint"), "node-build"),
+	}
+}
+
+// nodePackageManager picks the package manager from the lock file in dir.
+func nodePackageManager(dir string) string {
+	lockFiles := []struct {
+		file    string
+		manager string
+	}{
+		{"pnpm-lock.yaml", "pnpm"},
+		{"yarn.lock", "yarn"},
+		{"bun.lockb", "bun"},
+		{"bun.lock", "bun"},
+	}
+	for _, lockFile := range lockFiles {
+		if _, err := os.Stat(filepath.Join(dir, lockFile.file)); err == nil {
+			return lockFile.manager
+		}
+	}
+	return "npm"
+}
+
+// npmDefaultTest is the test script written by npm init, which always fails.
+const npmDefaultTest = `echo "Error: no test specified" && exit 1`
+
+// hasNodeScript reports whether package.json in dir defines the script.
+func hasNodeScript(dir, name string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json")) // #nosec G304 -- path is built from a detected project directory
+	if err != nil {
+		return false, err
+	}
+
+	var manifest struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, "package.json"), err)
+	}
+
+	script, ok := manifest.Scripts[name]
+	return ok && script != npmDefaultTest, nil
+}
+
+func rustChecks(c *Checker, p project) []check {
+	clippy := func(ctx context.Context, out io.Writer) error {
+		if !c.cfg.DryRun {
+			if _, err := exec.LookPath("cargo-clippy"); err != nil {
+				return skipCheck("clippy not installed")
+			}
+		}
+		return c.toolCommand(p.dir, "cargo", "clippy", "--", "-D", "warnings")(ctx, out)
+	}
+
+	return []check{
+		p.newCheck("cargo-build", "Cargo build", c.toolCommand(p.dir, "cargo", "build")),
+		p.newCheck("cargo-test", "Cargo tests", c.toolCommand(p.dir, "cargo", "test"), "cargo-build"),
+		p.newCheck("cargo-clippy", "Clippy", clippy, "cargo-build"),
+	}
+}
+
+func pythonChecks(c *Checker, p project) []check {
+	pytest := func(ctx context.Context, out io.Writer) error {
+		err := c.toolCommand(p.dir, "pytest")(ctx, out)
+		// pytest exits with 5 when it collects no tests
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 5 {
+			return skipCheck("no tests collected")
+		}
+		return err
+	}
+
+	return []check{
+		p.newCheck("python-test", "Python tests", pytest),
+		p.newCheck("python-lint", "Ruff", c.toolCommand(p.dir, "ruff", "check", ".")),
+	}
+}
+
+func mavenChecks(c *Checker, p project) []check {
+	mvn := wrapper(p.dir, "mvnw", "mvn")
+	return []check{
+		p.newCheck("maven-build", "Maven build", c.toolCommand(p.dir, mvn, "-B", "compile")),
+		p.newCheck("maven-verify", "Maven verify", c.toolCommand(p.dir, mvn, "-B", "verify"), "maven-build"),
+	}
+}
+
+func gradleChecks(c *Checker, p project) []check {
+	gradle := wrapper(p.dir, "gradlew", "gradle")
+	return []check{
+		p.newCheck("gradle-build", "Gradle build", c.toolCommand(p.dir, gradle, "assemble")),
+		p.newCheck("gradle-check", "Gradle check", c.toolCommand(p.dir, gradle, "check"), "gradle-build"),
+	}
+}
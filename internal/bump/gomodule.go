@@ -0,0 +1,106 @@
+package bump
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ypeckstadt/bump/internal/config"
+	"github.com/ypeckstadt/bump/internal/gomod"
+	"github.com/ypeckstadt/bump/internal/version"
+)
+
+// modulePathChange is a module path that must change for a new major
+// version of the Go module in root.
+type modulePathChange struct {
+	root    string
+	oldPath string
+	newPath string
+}
+
+// modulePathChangeFor returns the module path change newVersion requires
+// for the Go module in the repository root, or in the directory of the
+// selected module, or nil when there is none. Only SemVer major versions
+// are reflected in module paths.
+func (r *Release) modulePathChangeFor(ctx context.Context, newVersion *version.Version) (*modulePathChange, error) {
+	if r.cfg.Scheme != config.SchemeSemVer {
+		return nil, nil
+	}
+
+	root, err := r.moduleRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(root, "go.mod")); err != nil {
+		return nil, nil
+	}
+
+	oldPath, err := gomod.ModulePath(root)
+	if err != nil {
+		return nil, err
+	}
+	newPath, err := gomod.MajorPath(oldPath, newVersion.Major)
+	if err != nil {
+		return nil, err
+	}
+	if newPath == oldPath {
+		return nil, nil
+	}
+
+	return &modulePathChange{root: root, oldPath: oldPath, newPath: newPath}, nil
+}
+
+// checkModulePath returns the module path change to make before tagging
+// newVersion. With the block setting it refuses the release instead, except
+// in interactive mode, where it offers to make the change.
+func (r *Release) checkModulePath(ctx context.Context, newVersion *version.Version, interactive bool) (*modulePathChange, error) {
+	if r.cfg.GoModulePath == config.ModulePathIgnore {
+		return nil, nil
+	}
+
+	change, err := r.modulePathChangeFor(ctx, newVersion)
+	if err != nil || change == nil {
+		return nil, err
+	}
+
+	printWarning(fmt.Sprintf("⚠️  %s needs the module path %s instead of %s", r.tagName(newVersion), change.newPath, change.oldPath))
+	if r.cfg.GoModulePath == config.ModulePathRewrite {
+		return change, nil
+	}
+
+	if interactive {
+		fmt.Printf("Go only resolves %s for the module path %s, so go.mod and the imports of the module's own packages must change.\n", r.tagName(newVersion), change.newPath)
+		if r.confirmProceed(fmt.Sprintf("Rewrite the module path to %s and commit before tagging?", change.newPath)) {
+			return change, nil
+		}
+		return nil, fmt.Errorf("release cancelled")
+	}
+
+	return nil, fmt.Errorf("go get %s@%s fails unless the module path in go.mod and the module's own imports change to %s; use --go-module-path rewrite to change them in a commit before tagging, or --go-module-path ignore to tag anyway",
+		change.oldPath, r.tagName(newVersion), change.newPath)
+}
+
+// stageModulePath adds go.mod and the Go files whose imports of the
+// module's own packages change to the release commit.
+func (r *Release) stageModulePath(files *releaseFiles, change *modulePathChange) error {
+	changed, err := gomod.Rewrite(change.root, change.oldPath, change.newPath)
+	if err != nil {
+		return fmt.Errorf("failed to rewrite the module path: %w", err)
+	}
+
+	names := make([]string, 0, len(changed))
+	for name := range changed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := files.set(filepath.Join(change.root, filepath.FromSlash(name)), changed[name]); err != nil {
+			return err
+		}
+	}
+
+	printInfo(fmt.Sprintf("Changing the module path to %s in %d file(s)", change.newPath, len(names)))
+	return nil
+}
@@ -0,0 +1,270 @@
+package bump
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ypeckstadt/bump/internal/config"
+	"github.com/ypeckstadt/bump/internal/git"
+	"github.com/ypeckstadt/bump/internal/version"
+
+	"github.com/manifoldco/promptui"
+)
+
+const initTemplate = `# Configuration for bump (https://github.com/ypeckstadt/bump)
+# Flags and BUMP_* environment variables override these values.
+{{- if .Ecosystems}}
+# Detected project types: {{join .Ecosystems ", "}}
+{{- end}}
+
+# Prefix of version tags, e.g. "v" for v1.2.3 or "api@" for api@1.2.3
+tag_prefix: {{quote .TagPrefix}}
+# Or a full format with a suffix, which takes precedence over tag_prefix:
+# tag_format: "release-{version}-final"
+
+# Versioning scheme: semver, or calver for date-based versions such as 2026.10.3
+# scheme: calver
+# calver_format: "YYYY.0M.MICRO"
+
+# Remote that tags and branches are pushed to
+remote: {{quote .Remote}}
+
+# Pre-release identifier used by prepatch, preminor, premajor and prerelease;
+# empty keeps the channel of the current pre-release and starts new ones on rc
+preid: {{quote .Preid}}
+
+# Create a release branch for each tag, starting from source_branch
+create_branch: {{.CreateBranch}}
+source_branch: {{quote .SourceBranch}}
+auto_push: {{.AutoPush}}
+auto_merge: false
+
+# Prepend each release to the changelog and commit it before tagging
+changelog: {{.Changelog}}
+changelog_file: "CHANGELOG.md"
+commit_changelog: {{.Changelog}}
+
+# Files that record the version, updated and committed before tagging
+# version_files:
+#   - path: package.json
+#   - path: VERSION
+
+# Pre-release checks{{if .SkipChecksReason}} ({{.SkipChecksReason}}){{end}}
+skip_checks: {{.SkipChecks}}
+
+# Template for the annotated tag message
+tag_message_template: "Release {{"{{"}}.NewVersion{{"}}"}}"
+`
+
+// InitOptions holds the values written to a new configuration file.
+type InitOptions struct {
+	TagPrefix        string
+	Remote           string
+	Preid            string
+	CreateBranch     bool
+	SourceBranch     string
+	AutoPush         bool
+	Changelog        bool
+	SkipChecks       bool
+	SkipChecksReason string
+	Ecosystems       []string
+}
+
+type Initializer struct {
+	cfg *config.Config
+	git *git.Client
+}
+
+func NewInitializer(cfg *config.Config) *Initializer {
+	return &Initializer{
+		cfg: cfg,
+		git: git.NewClient(cfg),
+	}
+}
+
+// Run inspects the repository and writes a commented .bump.yaml to its root.
+// With assumeYes the detected defaults are written without prompting.
+func (i *Initializer) Run(ctx context.Context, assumeYes, force bool) error {
+	if !i.git.IsGitRepo(ctx) {
+		return fmt.Errorf("not a git repository")
+	}
+
+	root, err := i.git.GetRepoRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(root, config.RepoFileNames[0])
+	for _, name := range config.RepoFileNames {
+		existing := filepath.Join(root, name)
+		if _, err := os.Stat(existing); err == nil && !force {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", existing)
+		}
+	}
+
+	options := i.detect(ctx, root)
+	printInfo(fmt.Sprintf("Detected tag prefix %q and default branch %s", options.TagPrefix, options.SourceBranch))
+	if len(options.Ecosystems) > 0 {
+		printInfo(fmt.Sprintf("Detected project types: %s", strings.Join(options.Ecosystems, ", ")))
+	}
+
+	if !assumeYes {
+		if err := i.prompt(options); err != nil {
+			return err
+		}
+	}
+
+	content, err := renderInitConfig(options)
+	if err != nil {
+		return err
+	}
+
+	if i.cfg.DryRun {
+		printInfo(fmt.Sprintf("[DRY RUN] Would write %s:", path))
+		fmt.Println(content)
+		return nil
+	}
+
+	// #nosec G306 -- configuration files are meant to be committed and world readable
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	printSuccess(fmt.Sprintf("✅ Created %s", path))
+	return nil
+}
+
+func (i *Initializer) detect(ctx context.Context, root string) *InitOptions {
+	options := &InitOptions{
+		TagPrefix:    i.cfg.TagPrefix,
+		Remote:       i.cfg.Remote,
+		Preid:        i.cfg.Preid,
+		SourceBranch: "main",
+	}
+
+	if branch, err := i.git.GetDefaultBranch(ctx); err == nil {
+		options.SourceBranch = branch
+	}
+
+	if tags, err := i.git.GetAllTags(ctx); err == nil {
+		if prefix, ok := detectTagPrefix(tags); ok {
+			options.TagPrefix = prefix
+		}
+	}
+
+	if projects, err := detectProjects(root); err == nil {
+		seen := make(map[string]bool)
+		for _, p := range projects {
+			if !seen[p.ecosystem.name] {
+				seen[p.ecosystem.name] = true
+				options.Ecosystems = append(options.Ecosystems, p.ecosystem.name)
+			}
+		}
+	}
+
+	if len(options.Ecosystems) == 0 {
+		options.SkipChecks = true
+		options.SkipChecksReason = "no supported project type detected"
+	}
+
+	return options
+}
+
+func (i *Initializer) prompt(options *InitOptions) error {
+	var err error
+
+	if options.TagPrefix, err = promptString("Tag prefix", options.TagPrefix); err != nil {
+		return err
+	}
+	if options.Remote, err = promptString("Remote", options.Remote); err != nil {
+		return err
+	}
+	if options.Preid, err = promptString("Pre-release identifier", options.Preid); err != nil {
+		return err
+	}
+
+	options.CreateBranch = promptConfirm("Create a release branch for each tag?")
+	if options.CreateBranch {
+		if options.SourceBranch, err = promptString("Source branch", options.SourceBranch); err != nil {
+			return err
+		}
+		options.AutoPush = promptConfirm("Push release branches automatically?")
+	}
+
+	options.Changelog = promptConfirm("Maintain CHANGELOG.md?")
+
+	return nil
+}
+
+// tagPrefixPattern splits a tag into a prefix and a trailing version.
+var tagPrefixPattern = regexp.MustCompile(`^(.*?)(\d+\.\d+\.\d+.*)$`)
+
+// detectTagPrefix returns the most common prefix among version tags, given
+// as "<tag> <date>" lines.
+func detectTagPrefix(lines []string) (string, bool) {
+	counts := make(map[string]int)
+	best := ""
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		matches := tagPrefixPattern.FindStringSubmatch(fields[0])
+		if matches == nil {
+			continue
+		}
+		if _, err := (version.TagFormat{Prefix: matches[1]}).Parse(fields[0]); err != nil {
+			continue
+		}
+		counts[matches[1]]++
+		if counts[matches[1]] > counts[best] {
+			best = matches[1]
+		}
+	}
+
+	return best, counts[best] > 0
+}
+
+func renderInitConfig(options *InitOptions) (string, error) {
+	funcs := map[string]interface{}{
+		"join":  strings.Join,
+		"quote": strconv.Quote,
+	}
+
+	tmpl, err := template.New("config").Funcs(funcs).Parse(initTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid config template: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, options); err != nil {
+		return "", fmt.Errorf("failed to render config: %w", err)
+	}
+	return b.String(), nil
+}
+
+func promptString(label, defaultValue string) (string, error) {
+	prompt := promptui.Prompt{
+		Label:     label,
+		Default:   defaultValue,
+		AllowEdit: true,
+	}
+
+	return prompt.Run()
+}
+
+func promptConfirm(label string) bool {
+	prompt := promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
+	}
+
+	result, err := prompt.Run()
+	return err == nil && (result == "y" || result == "yes")
+}
@@ -0,0 +1,418 @@
+package bump
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/ypeckstadt/bump/internal/config"
+	"github.com/ypeckstadt/bump/internal/version"
+)
+
+// journalPath is the journal of the release in progress, relative to the
+// git directory.
+const journalPath = "bump/release.json"
+
+// The steps of a release, in the order they run.
+const (
+	stepCommit    = "commit"
+	stepChangelog = "changelog"
+	stepTag       = "tag"
+	stepBranch    = "branch"
+	stepPush      = "push"
+)
+
+// journal is the state of a release in progress. It is saved after every
+// step and change, so that a release that died part way can be resumed
+// from its first incomplete step or aborted with bump resume and bump
+// abort.
+type journal struct {
+	Tag     string `json:"tag"`
+	Version string `json:"version"`
+	// PreviousTag is the tag of the version being bumped, empty for the
+	// first release
+	PreviousTag string    `json:"previous_tag"`
+	VersionType string    `json:"version_type"`
+	Message     string    `json:"message"`
+	ModulePath  bool      `json:"module_path,omitempty"`
+	Started     time.Time `json:"started"`
+	// Branch is the branch the release started on, empty on a detached
+	// HEAD
+	Branch string `json:"branch"`
+	// Options are the options the release started with; a journal written
+	// before they were recorded has none
+	Options   *releaseOptions `json:"options,omitempty"`
+	Planned   []string        `json:"planned"`
+	Completed []string        `json:"completed"`
+	// Committed and PushBranch are the results of the commit and branch
+	// steps that the push step needs
+	Committed  bool     `json:"committed,omitempty"`
+	PushBranch string   `json:"push_branch,omitempty"`
+	Changes    []change `json:"changes"`
+	// RolledBack is set when rolling back failed part way, so the release
+	// can only be aborted
+	RolledBack bool `json:"rolled_back,omitempty"`
+}
+
+// releaseOptions are the options that shape the steps of a release. They
+// are kept in the journal so a resumed release finishes the way it
+// started, whatever flags and configuration bump resume runs with.
+type releaseOptions struct {
+	Path                 string                     `json:"path"`
+	TagPrefix            string                     `json:"tag_prefix"`
+	TagFormat            string                     `json:"tag_format"`
+	Scheme               string                     `json:"scheme"`
+	CalVerFormat         string                     `json:"calver_format"`
+	Remote               string                     `json:"remote"`
+	NoBranch             bool                       `json:"nobranch"`
+	CreateBranch         bool                       `json:"create_branch"`
+	SourceBranch         string                     `json:"source_branch"`
+	BranchName           string                     `json:"branch_name"`
+	AutoMerge            bool                       `json:"auto_merge"`
+	AutoPush             bool                       `json:"auto_push"`
+	Changelog            bool                       `json:"changelog"`
+	ChangelogFile        string                     `json:"changelog_file"`
+	CommitChangelog      bool                       `json:"commit_changelog"`
+	VersionFiles         []config.VersionFileConfig `json:"version_files"`
+	ReleaseCommitMessage string                     `json:"release_commit_message"`
+	SignCommits          bool                       `json:"sign_commits"`
+	GoModulePath         string                     `json:"go_module_path"`
+}
+
+func optionsOf(cfg *config.Config) *releaseOptions {
+	return &releaseOptions{
+		Path:                 cfg.Path,
+		TagPrefix:            cfg.TagPrefix,
+		TagFormat:            cfg.TagFormat,
+		Scheme:               cfg.Scheme,
+		CalVerFormat:         cfg.CalVerFormat,
+		Remote:               cfg.Remote,
+		NoBranch:             cfg.NoBranch,
+		CreateBranch:         cfg.CreateBranch,
+		SourceBranch:         cfg.SourceBranch,
+		BranchName:           cfg.BranchName,
+		AutoMerge:            cfg.AutoMerge,
+		AutoPush:             cfg.AutoPush,
+		Changelog:            cfg.Changelog,
+		ChangelogFile:        cfg.ChangelogFile,
+		CommitChangelog:      cfg.CommitChangelog,
+		VersionFiles:         slices.Clone(cfg.VersionFiles),
+		ReleaseCommitMessage: cfg.ReleaseCommitMessage,
+		SignCommits:          cfg.SignCommits,
+		GoModulePath:         cfg.GoModulePath,
+	}
+}
+
+// apply sets the options in cfg.
+func (o *releaseOptions) apply(cfg *config.Config) {
+	cfg.Path = o.Path
+	cfg.TagPrefix = o.TagPrefix
+	cfg.TagFormat = o.TagFormat
+	cfg.Scheme = o.Scheme
+	cfg.CalVerFormat = o.CalVerFormat
+	cfg.Remote = o.Remote
+	cfg.NoBranch = o.NoBranch
+	cfg.CreateBranch = o.CreateBranch
+	cfg.SourceBranch = o.SourceBranch
+	cfg.BranchName = o.BranchName
+	cfg.AutoMerge = o.AutoMerge
+	cfg.AutoPush = o.AutoPush
+	cfg.Changelog = o.Changelog
+	cfg.ChangelogFile = o.ChangelogFile
+	cfg.CommitChangelog = o.CommitChangelog
+	cfg.VersionFiles = slices.Clone(o.VersionFiles)
+	cfg.ReleaseCommitMessage = o.ReleaseCommitMessage
+	cfg.SignCommits = o.SignCommits
+	cfg.GoModulePath = o.GoModulePath
+}
+
+// transaction is a release being run with its journal. Nothing is saved in
+// a dry run, as nothing is changed.
+type transaction struct {
+	journal
+	path string
+	// step is the step being run, which records the changes made
+	step string
+}
+
+// record adds a change made by the current step to the journal.
+func (t *transaction) record(c change) {
+	if t.path == "" {
+		return
+	}
+	c.Step = t.step
+	t.Changes = append(t.Changes, c)
+	t.save()
+}
+
+// complete marks step as done.
+func (t *transaction) complete(step string) {
+	t.Completed = append(t.Completed, step)
+	t.save()
+}
+
+// next returns the first step that is not complete, or "" when all are.
+func (t *transaction) next() string {
+	for _, step := range t.Planned {
+		if !slices.Contains(t.Completed, step) {
+			return step
+		}
+	}
+	return ""
+}
+
+// save writes the journal. A journal that cannot be written is reported
+// without failing the release, which can still be rolled back in memory.
+func (t *transaction) save() {
+	if t.path == "" {
+		return
+	}
+	if err := writeJournal(t.path, &t.journal); err != nil {
+		printWarning(fmt.Sprintf("⚠️  Could not save the release journal: %v", err))
+	}
+}
+
+// remove deletes the journal once the release is done or rolled back.
+func (t *transaction) remove() {
+	if t.path == "" {
+		return
+	}
+	if err := os.Remove(t.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		printWarning(fmt.Sprintf("⚠️  Could not remove the release journal: %v", err))
+	}
+}
+
+func writeJournal(path string, j *journal) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	// Replace the journal in one step so it is never left half written
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// beginRelease plans the steps of releasing newVersion and saves the
+// journal, refusing to start while another release is in progress.
+func (r *Release) beginRelease(ctx context.Context, newVersion *version.Version, versionType string, modulePath bool, message string) (*transaction, error) {
+	if err := r.checkNoReleaseInProgress(ctx); err != nil {
+		return nil, err
+	}
+
+	branch, err := r.git.GetCurrentBranch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &transaction{journal: journal{
+		Tag:         r.tagName(newVersion),
+		Version:     newVersion.String(),
+		PreviousTag: r.version.Raw,
+		VersionType: versionType,
+		Message:     message,
+		ModulePath:  modulePath,
+		Started:     time.Now().UTC(),
+		Branch:      branch,
+		Options:     optionsOf(r.cfg),
+		Planned:     []string{stepCommit},
+		Completed:   []string{},
+		Changes:     []change{},
+	}}
+	if r.cfg.Changelog && !r.cfg.CommitChangelog {
+		tx.Planned = append(tx.Planned, stepChangelog)
+	}
+	tx.Planned = append(tx.Planned, stepTag, stepBranch, stepPush)
+
+	if !r.cfg.DryRun {
+		if tx.path, err = r.git.GitPath(ctx, journalPath); err != nil {
+			return nil, err
+		}
+		if err := writeJournal(tx.path, &tx.journal); err != nil {
+			return nil, fmt.Errorf("failed to save the release journal: %w", err)
+		}
+	}
+
+	return tx, nil
+}
+
+// runSteps runs the steps of the release that are not complete yet. When a
+// step fails, the changes of the release are rolled back.
+func (r *Release) runSteps(ctx context.Context, tx *transaction, newVersion *version.Version) error {
+	for _, step := range tx.Planned {
+		if slices.Contains(tx.Completed, step) {
+			continue
+		}
+		tx.step = step
+		if err := r.runStep(ctx, tx, step, newVersion); err != nil {
+			return r.rollback(ctx, tx, err)
+		}
+		tx.complete(step)
+	}
+
+	tx.remove()
+	return nil
+}
+
+// checkNoReleaseInProgress fails when the journal of an earlier release
+// exists.
+func (r *Release) checkNoReleaseInProgress(ctx context.Context) error {
+	tx, err := r.openJournal(ctx)
+	if err != nil {
+		if errors.Is(err, errNoRelease) {
+			return nil
+		}
+		return err
+	}
+	return fmt.Errorf("the release of %s is still in progress; run 'bump resume' to continue it or 'bump abort' to undo it", tx.Tag)
+}
+
+var errNoRelease = errors.New("no release in progress")
+
+// openJournal loads the journal of the release in progress.
+func (r *Release) openJournal(ctx context.Context) (*transaction, error) {
+	path, err := r.git.GitPath(ctx, journalPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- journal in the git directory
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNoRelease
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the release journal: %w", err)
+	}
+
+	tx := &transaction{path: path}
+	if err := json.Unmarshal(data, &tx.journal); err != nil {
+		return nil, fmt.Errorf("failed to parse the release journal %s: %w", path, err)
+	}
+	if r.cfg.DryRun {
+		// Show what would happen without touching the journal
+		tx.path = ""
+	}
+	return tx, nil
+}
+
+// printJournal shows the release in progress and its steps.
+func printJournal(tx *transaction) {
+	printInfo(fmt.Sprintf("Release of %s (%s) started %s on %s", tx.Tag, tx.VersionType, tx.Started.Local().Format("2006-01-02 15:04:05"), branchOrHead(tx.Branch)))
+	next := tx.next()
+	for _, step := range tx.Planned {
+		mark := "[ ]"
+		switch {
+		case slices.Contains(tx.Completed, step):
+			mark = "[x]"
+		case step == next && !tx.RolledBack:
+			mark = "[>]"
+		}
+		fmt.Printf("  %s %s\n", mark, step)
+	}
+}
+
+func branchOrHead(branch string) string {
+	if branch == "" {
+		return "a detached HEAD"
+	}
+	return "branch " + branch
+}
+
+// Resume continues the release in progress from its first incomplete step,
+// after undoing whatever that step had done before the release stopped.
+func (r *Release) Resume(ctx context.Context) error {
+	tx, err := r.openJournal(ctx)
+	if err != nil {
+		return err
+	}
+	printJournal(tx)
+
+	if tx.RolledBack {
+		return fmt.Errorf("the release of %s failed and was partly rolled back; run 'bump abort' to finish undoing it", tx.Tag)
+	}
+
+	step := tx.next()
+	if r.cfg.DryRun {
+		printInfo(fmt.Sprintf("[DRY RUN] Would resume the release of %s from the %s step", tx.Tag, step))
+		return nil
+	}
+
+	if !r.undoChanges(ctx, tx, step) {
+		return fmt.Errorf("failed to undo the interrupted %s step; fix the problem and run 'bump resume' again", step)
+	}
+
+	if tx.Branch != "" {
+		current, err := r.git.GetCurrentBranch(ctx)
+		if err != nil {
+			return err
+		}
+		if current != tx.Branch {
+			return fmt.Errorf("the release started on branch %s, but %s is checked out", tx.Branch, branchOrHead(current))
+		}
+	}
+
+	// Finish the release with the options it started with
+	if tx.Options != nil {
+		if !reflect.DeepEqual(optionsOf(r.cfg), tx.Options) {
+			printWarning("⚠️  The options differ from those the release started with; resuming with the options of the release")
+		}
+		tx.Options.apply(r.cfg)
+	}
+
+	newVersion, err := r.cfg.VersionScheme().Parse(tx.Version)
+	if err != nil {
+		return fmt.Errorf("invalid version %s in the release journal: %w", tx.Version, err)
+	}
+
+	// Once tagged, the new tag is the latest one, so the version being
+	// bumped comes from the journal
+	r.version = version.Zero(r.cfg.VersionScheme())
+	if tx.PreviousTag != "" {
+		if r.version, err = r.cfg.VersionTagFormat().Parse(tx.PreviousTag); err != nil {
+			return fmt.Errorf("invalid previous tag %s in the release journal: %w", tx.PreviousTag, err)
+		}
+	}
+
+	printInfo(fmt.Sprintf("Resuming the release of %s from the %s step", tx.Tag, step))
+	return r.runSteps(ctx, tx, newVersion)
+}
+
+// Abort undoes the changes of the release in progress and removes its
+// journal. It only touches the local repository.
+func (r *Release) Abort(ctx context.Context) error {
+	tx, err := r.openJournal(ctx)
+	if err != nil {
+		return err
+	}
+	printJournal(tx)
+
+	if r.cfg.DryRun {
+		for i := len(tx.Changes) - 1; i >= 0; i-- {
+			printInfo(fmt.Sprintf("[DRY RUN] Would %s", tx.Changes[i].describe(false)))
+		}
+		return nil
+	}
+
+	printWarning(fmt.Sprintf("Aborting the release of %s", tx.Tag))
+	if !r.undoChanges(context.WithoutCancel(ctx), tx, "") {
+		tx.RolledBack = true
+		tx.save()
+		return fmt.Errorf("failed to undo some changes of the release of %s; fix the problem and run 'bump abort' again", tx.Tag)
+	}
+	tx.remove()
+
+	printSuccess(fmt.Sprintf("✅ Aborted the release of %s", tx.Tag))
+	return nil
+}
@@ -0,0 +1,83 @@
+package bump
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/ypeckstadt/bump/internal/changelog"
+	"github.com/ypeckstadt/bump/internal/conventional"
+	"github.com/ypeckstadt/bump/internal/version"
+)
+
+// DefaultTagMessageTemplate is used when no tag message template is
+// configured.
+const DefaultTagMessageTemplate = "Release {{.NewVersion}}"
+
+// MessageData is the data available to tag message templates.
+type MessageData struct {
+	OldVersion string
+	NewVersion string
+	BumpType   string
+	Commits    []conventional.Commit
+	Groups     []changelog.Group
+	Author     string
+	Date       time.Time
+	Branch     string
+}
+
+func (r *Release) buildMessageData(ctx context.Context, newVersion *version.Version, bumpType string) *MessageData {
+	data := &MessageData{
+		OldVersion: r.tagName(r.version),
+		NewVersion: r.tagName(newVersion),
+		BumpType:   bumpType,
+		Date:       time.Now(),
+	}
+
+	if analysis, err := r.analyzeCommits(ctx); err == nil {
+		data.Commits = analysis.Commits
+		data.Groups = changelog.GroupCommits(analysis.Commits)
+	}
+	if author, err := r.git.GetUserName(ctx); err == nil {
+		data.Author = author
+	}
+	if branch, err := r.git.GetCurrentBranch(ctx); err == nil {
+		data.Branch = branch
+	}
+
+	return data
+}
+
+// tagMessage renders the configured tag message template for newVersion.
+func (r *Release) tagMessage(ctx context.Context, newVersion *version.Version, bumpType string) (string, error) {
+	text := r.cfg.TagMessageTemplate
+	if r.cfg.TagMessageTemplateFile != "" {
+		content, err := os.ReadFile(r.cfg.TagMessageTemplateFile) // #nosec G304 -- path is provided by the user
+		if err != nil {
+			return "", fmt.Errorf("failed to read tag message template: %w", err)
+		}
+		text = string(content)
+	}
+	if text == "" {
+		text = DefaultTagMessageTemplate
+	}
+
+	return renderTemplate("tag message", text, r.buildMessageData(ctx, newVersion, bumpType))
+}
+
+func renderTemplate(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
@@ -1,10 +1,17 @@
 package bump
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/ypeckstadt/bump/internal/changelog"
 	"github.com/ypeckstadt/bump/internal/config"
+	"github.com/ypeckstadt/bump/internal/conventional"
 	"github.com/ypeckstadt/bump/internal/git"
 	"github.com/ypeckstadt/bump/internal/version"
 
@@ -18,10 +25,14 @@ type Release struct {
 	version *version.Version
 }
 
-func NewRelease(cfg *config.Config) *Release {
+func NewRelease(ctx context.Context, cfg *config.Config) *Release {
 	gitClient := git.NewClient(cfg)
-	currentVersionStr := GetCurrentVersion()
-	ver := version.NewFromString(currentVersionStr)
+	ver := version.Zero(cfg.VersionScheme())
+	if tag, err := gitClient.GetLatestTag(ctx); err == nil {
+		if parsed, err := cfg.VersionTagFormat().Parse(tag); err == nil {
+			ver = parsed
+		}
+	}
 
 	return &Release{
 		cfg:     cfg,
@@ -30,14 +41,18 @@ func NewRelease(cfg *config.Config) *Release {
 	}
 }
 
-func (r *Release) RunInteractive() error {
+func (r *Release) RunInteractive(ctx context.Context) error {
 	printInfo("🚀 Interactive Release Mode")
 
-	if !r.git.IsGitRepo() {
+	if !r.git.IsGitRepo(ctx) {
 		return fmt.Errorf("not a git repository")
 	}
 
-	clean, err := r.git.IsWorkingDirectoryClean()
+	if err := r.checkNoReleaseInProgress(ctx); err != nil {
+		return err
+	}
+
+	clean, err := r.git.IsWorkingDirectoryClean(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to check working directory: %w", err)
 	}
@@ -49,9 +64,9 @@ func (r *Release) RunInteractive() error {
 		}
 	}
 
-	printInfo(fmt.Sprintf("Current version: %s", r.version.String()))
+	printInfo(fmt.Sprintf("Current version: %s", r.tagName(r.version)))
 
-	commits, err := r.git.GetCommitsSinceTag(r.version.Raw)
+	commits, err := r.git.GetCommitsSinceTag(ctx, r.version.Raw)
 	if err != nil {
 		printWarning("Could not get commits since last tag")
 	} else if len(commits) > 0 {
@@ -65,163 +80,584 @@ func (r *Release) RunInteractive() error {
 		}
 	}
 
-	versionType, err := r.promptVersionType()
+	suggested := ""
+	analysis, err := r.analyzeCommits(ctx)
+	if err != nil {
+		printWarning("Could not analyze commits since last tag")
+	} else {
+		printAnalysis(analysis)
+		suggested = analysis.BumpType
+	}
+
+	versionType, err := r.promptVersionType(suggested)
 	if err != nil {
 		return err
 	}
 
-	newVersion, err := r.version.Bump(versionType)
+	versionType, err = r.checkAPICompatibility(ctx, versionType, false, true)
 	if err != nil {
 		return err
 	}
 
-	printInfo(fmt.Sprintf("New version will be: %s", newVersion.String()))
+	newVersion, err := r.version.Bump(versionType, r.cfg.Preid)
+	if err != nil {
+		return err
+	}
+
+	printInfo(fmt.Sprintf("New version will be: %s", r.tagName(newVersion)))
+
+	if r.git.TagExists(ctx, r.tagName(newVersion)) {
+		return fmt.Errorf("tag %s already exists", r.tagName(newVersion))
+	}
+
+	modulePath, err := r.checkModulePath(ctx, newVersion, true)
+	if err != nil {
+		return err
+	}
 
-	if r.git.TagExists(newVersion.String()) {
-		return fmt.Errorf("tag %s already exists", newVersion.String())
+	if err := r.runPreReleaseChecks(ctx); err != nil {
+		return err
 	}
 
-	if err := r.runPreReleaseChecks(); err != nil {
+	defaultMessage, err := r.tagMessage(ctx, newVersion, versionType)
+	if err != nil {
 		return err
 	}
 
-	message, err := r.promptReleaseMessage(newVersion.String())
+	message, err := r.promptReleaseMessage(defaultMessage)
 	if err != nil {
 		return err
 	}
 
-	if !r.confirmProceed(fmt.Sprintf("Create and push tag %s?", newVersion.String())) {
+	if !r.confirmProceed(fmt.Sprintf("Create and push tag %s?", r.tagName(newVersion))) {
 		return fmt.Errorf("release cancelled")
 	}
 
-	return r.createAndPushTag(newVersion.String(), message)
+	return r.release(ctx, newVersion, versionType, modulePath, message)
 }
 
-func (r *Release) RunQuick(versionType string) error {
-	if !r.git.IsGitRepo() {
+func (r *Release) RunQuick(ctx context.Context, versionType string) error {
+	if !r.git.IsGitRepo(ctx) {
 		return fmt.Errorf("not a git repository")
 	}
 
-	newVersion, err := r.version.Bump(versionType)
+	if err := r.checkNoReleaseInProgress(ctx); err != nil {
+		return err
+	}
+
+	auto := versionType == "auto"
+	versionType, err := r.resolveVersionType(ctx, versionType)
+	if err != nil {
+		return err
+	}
+	if versionType == conventional.BumpNone {
+		printInfo("No release needed")
+		return nil
+	}
+
+	versionType, err = r.checkAPICompatibility(ctx, versionType, auto, false)
+	if err != nil {
+		return err
+	}
+
+	newVersion, err := r.version.Bump(versionType, r.cfg.Preid)
 	if err != nil {
 		return err
 	}
 
-	if r.git.TagExists(newVersion.String()) {
-		return fmt.Errorf("tag %s already exists", newVersion.String())
+	if r.git.TagExists(ctx, r.tagName(newVersion)) {
+		return fmt.Errorf("tag %s already exists", r.tagName(newVersion))
 	}
 
-	message := fmt.Sprintf("Release %s", newVersion.String())
+	modulePath, err := r.checkModulePath(ctx, newVersion, false)
+	if err != nil {
+		return err
+	}
 
-	printInfo(fmt.Sprintf("Creating %s release: %s → %s", versionType, r.version.String(), newVersion.String()))
+	if r.cfg.QuickChecks {
+		if err := r.runPreReleaseChecks(ctx); err != nil {
+			return err
+		}
+	}
 
-	return r.createAndPushTag(newVersion.String(), message)
+	message, err := r.tagMessage(ctx, newVersion, versionType)
+	if err != nil {
+		return err
+	}
+
+	printInfo(fmt.Sprintf("Creating %s release: %s → %s", versionType, r.tagName(r.version), r.tagName(newVersion)))
+
+	return r.release(ctx, newVersion, versionType, modulePath, message)
 }
 
-func (r *Release) promptVersionType() (string, error) {
-	patchVersion := r.version.BumpPatch()
-	minorVersion := r.version.BumpMinor()
-	majorVersion := r.version.BumpMajor()
+// GenerateChangelog prepends a section for the next version to the
+// changelog without creating a tag.
+func (r *Release) GenerateChangelog(ctx context.Context, versionType string) error {
+	if !r.git.IsGitRepo(ctx) {
+		return fmt.Errorf("not a git repository")
+	}
 
-	prompt := promptui.Select{
-		Label: "Select version type",
-		Items: []string{
-			fmt.Sprintf("patch (%s) - bug fixes", patchVersion.String()),
-			fmt.Sprintf("minor (%s) - new features", minorVersion.String()),
-			fmt.Sprintf("major (%s) - breaking changes", majorVersion.String()),
-		},
+	if err := r.checkNoReleaseInProgress(ctx); err != nil {
+		return err
 	}
 
-	_, result, err := prompt.Run()
+	auto := versionType == "auto"
+	versionType, err := r.resolveVersionType(ctx, versionType)
 	if err != nil {
-		return "", err
+		return err
+	}
+	if versionType == conventional.BumpNone {
+		printInfo("No release needed")
+		return nil
+	}
+
+	versionType, err = r.checkAPICompatibility(ctx, versionType, auto, false)
+	if err != nil {
+		return err
+	}
+
+	newVersion, err := r.version.Bump(versionType, r.cfg.Preid)
+	if err != nil {
+		return err
+	}
+
+	return r.updateChangelog(ctx, newVersion)
+}
+
+// release makes the release commit with the files the release changes,
+// tags it and pushes the commit and the tag together. Without changed files
+// the current HEAD is tagged. The steps are recorded in a journal, and when
+// a step fails, the steps already done are rolled back.
+func (r *Release) release(ctx context.Context, newVersion *version.Version, versionType string, modulePath *modulePathChange, message string) error {
+	tx, err := r.beginRelease(ctx, newVersion, versionType, modulePath != nil, message)
+	if err != nil {
+		return err
+	}
+
+	return r.runSteps(ctx, tx, newVersion)
+}
+
+// runStep runs one step of the release recorded in tx.
+func (r *Release) runStep(ctx context.Context, tx *transaction, step string, newVersion *version.Version) error {
+	switch step {
+	case stepCommit:
+		committed, err := r.commitStep(ctx, tx, newVersion)
+		tx.Committed = committed
+		return err
+	case stepChangelog:
+		return r.writeChangelog(ctx, tx, newVersion)
+	case stepTag:
+		printInfo(fmt.Sprintf("Creating tag %s...", tx.Tag))
+		tx.record(change{Kind: changeTag, Ref: tx.Tag})
+		return r.git.CreateTag(ctx, tx.Tag, tx.Message)
+	case stepBranch:
+		branch, err := r.releaseBranch(ctx, tx, tx.Tag)
+		tx.PushBranch = branch
+		return err
+	case stepPush:
+		return r.pushRelease(ctx, tx)
+	}
+
+	return fmt.Errorf("unknown release step %q", step)
+}
+
+// commitStep makes the release commit with the module path change, the
+// version files and, when committed, the changelog.
+func (r *Release) commitStep(ctx context.Context, tx *transaction, newVersion *version.Version) (bool, error) {
+	files := newReleaseFiles()
+
+	if tx.ModulePath {
+		modulePath, err := r.modulePathChangeFor(ctx, newVersion)
+		if err != nil {
+			return false, err
+		}
+		if modulePath != nil {
+			if err := r.stageModulePath(files, modulePath); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if err := r.stageVersionFiles(ctx, files, newVersion); err != nil {
+		return false, err
+	}
+
+	if r.cfg.Changelog && r.cfg.CommitChangelog {
+		if err := r.stageChangelog(ctx, files, newVersion); err != nil {
+			return false, err
+		}
+	}
+
+	return r.commitRelease(ctx, tx, files, newVersion, tx.VersionType)
+}
+
+// writeChangelog prepends the section for newVersion to the changelog
+// outside the release commit, recording its previous content.
+func (r *Release) writeChangelog(ctx context.Context, tx *transaction, newVersion *version.Version) error {
+	changelogFile, err := r.changelogFile(ctx)
+	if err != nil {
+		return err
+	}
+	previous, err := os.ReadFile(changelogFile) // #nosec G304 -- configured changelog file
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", changelogFile, err)
+	}
+
+	path := changelogFile
+	if abs, err := filepath.Abs(changelogFile); err == nil {
+		path = abs
+	}
+	tx.record(change{Kind: changeFile, Ref: path, Content: previous})
+	return r.updateChangelog(ctx, newVersion)
+}
+
+// stageChangelog adds the changelog with the section for newVersion to the
+// release commit.
+func (r *Release) stageChangelog(ctx context.Context, files *releaseFiles, newVersion *version.Version) error {
+	changelogFile, changelogVersion, section, err := r.changelogSection(ctx, newVersion)
+	if err != nil {
+		return err
+	}
+
+	content, err := files.read(changelogFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", changelogFile, err)
+	}
+	updated, err := changelog.Insert(string(content), changelogVersion, section, changelog.Header(r.cfg.VersionScheme()))
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", changelogFile, err)
+	}
+
+	printInfo(fmt.Sprintf("Updating %s", changelogFile))
+	return files.set(changelogFile, []byte(updated))
+}
+
+// changelogSection returns the changelog file and the version and section
+// to add to it for newVersion.
+func (r *Release) changelogSection(ctx context.Context, newVersion *version.Version) (string, string, string, error) {
+	analysis, err := r.analyzeCommits(ctx)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to analyze commits: %w", err)
+	}
+
+	changelogFile, err := r.changelogFile(ctx)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	changelogVersion := newVersion.String()
+	return changelogFile, changelogVersion, changelog.Render(changelogVersion, time.Now(), analysis.Commits), nil
+}
+
+// updateChangelog prepends the section for newVersion to the changelog and,
+// when configured, commits it on its own.
+func (r *Release) updateChangelog(ctx context.Context, newVersion *version.Version) error {
+	changelogFile, changelogVersion, section, err := r.changelogSection(ctx, newVersion)
+	if err != nil {
+		return err
 	}
 
-	if strings.Contains(result, "patch") {
-		return "patch", nil
-	} else if strings.Contains(result, "minor") {
-		return "minor", nil
+	if r.cfg.DryRun {
+		printInfo(fmt.Sprintf("[DRY RUN] Would prepend to %s:", changelogFile))
+		fmt.Println(section)
 	} else {
-		return "major", nil
+		if err := changelog.Prepend(changelogFile, changelogVersion, section, changelog.Header(r.cfg.VersionScheme())); err != nil {
+			return err
+		}
+		printSuccess(fmt.Sprintf("✅ Updated %s", changelogFile))
+	}
+
+	if r.cfg.CommitChangelog {
+		message := fmt.Sprintf("chore(release): update changelog for %s", r.tagName(newVersion))
+		if err := r.git.CommitFiles(ctx, message, changelogFile); err != nil {
+			return err
+		}
+		printSuccess(fmt.Sprintf("✅ Committed %s", changelogFile))
+	}
+
+	return nil
+}
+
+// ShowNext prints the version type and version suggested by the commits
+// since the current version without creating anything.
+func (r *Release) ShowNext(ctx context.Context) error {
+	if !r.git.IsGitRepo(ctx) {
+		return fmt.Errorf("not a git repository")
+	}
+
+	analysis, err := r.analyzeCommits(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to analyze commits: %w", err)
+	}
+
+	printInfo(fmt.Sprintf("Current version: %s", r.tagName(r.version)))
+	printAnalysis(analysis)
+
+	if analysis.BumpType == conventional.BumpNone {
+		printInfo("No release needed")
+		return nil
+	}
+
+	versionType, err := r.checkAPICompatibility(ctx, analysis.BumpType, true, false)
+	if err != nil {
+		return err
+	}
+
+	newVersion, err := r.version.Bump(versionType, r.cfg.Preid)
+	if err != nil {
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Next version: %s", r.tagName(newVersion)))
+
+	if r.cfg.GoModulePath != config.ModulePathIgnore {
+		if change, err := r.modulePathChangeFor(ctx, newVersion); err == nil && change != nil {
+			printWarning(fmt.Sprintf("⚠️  %s needs the module path %s instead of %s", r.tagName(newVersion), change.newPath, change.oldPath))
+		}
+	}
+	return nil
+}
+
+// resolveVersionType replaces "auto" with the version type suggested by the
+// commits since the current version, which is conventional.BumpNone when no
+// release is needed.
+func (r *Release) resolveVersionType(ctx context.Context, versionType string) (string, error) {
+	if versionType != "auto" {
+		return versionType, nil
+	}
+
+	analysis, err := r.analyzeCommits(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to analyze commits: %w", err)
+	}
+	printAnalysis(analysis)
+
+	return analysis.BumpType, nil
+}
+
+// analyzeCommits classifies the commits since the current version according
+// to Conventional Commits.
+func (r *Release) analyzeCommits(ctx context.Context) (*conventional.Analysis, error) {
+	gitCommits, err := r.git.GetCommitMessagesSinceTag(ctx, r.version.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]conventional.Commit, 0, len(gitCommits))
+	for _, commit := range gitCommits {
+		commits = append(commits, conventional.Parse(commit.Hash, commit.Subject, commit.Body))
+	}
+
+	return conventional.Analyze(commits), nil
+}
+
+func printAnalysis(analysis *conventional.Analysis) {
+	printInfo(fmt.Sprintf("Suggested version type: %s (%s)", analysis.BumpType, analysis.Summary()))
+	for i, commit := range analysis.Reasons {
+		if i >= 5 {
+			fmt.Printf("  ... and %d more\n", len(analysis.Reasons)-5)
+			break
+		}
+		fmt.Printf("  %s\n", commit)
+	}
+}
+
+func (r *Release) promptVersionType(suggested string) (string, error) {
+	type versionOption struct {
+		versionType string
+		description string
+	}
+
+	preid := r.cfg.Preid
+	if preid == "" {
+		preid = version.DefaultPreid
+	}
+
+	options := []versionOption{
+		{"patch", "bug fixes"},
+		{"minor", "new features"},
+		{"major", "breaking changes"},
+		{"prerelease", "next pre-release build"},
+		{"prepatch", fmt.Sprintf("%s of next patch", preid)},
+		{"preminor", fmt.Sprintf("%s of next minor", preid)},
+		{"premajor", fmt.Sprintf("%s of next major", preid)},
+	}
+	if r.cfg.Preid != "" {
+		options[3].description = fmt.Sprintf("next %s build", r.cfg.Preid)
+	}
+	if r.cfg.Scheme == config.SchemeCalVer {
+		options[0].description = "next release"
+	}
+	if r.version.IsPrerelease() {
+		options = append(options, versionOption{"release", "finalise pre-release"})
+	}
+
+	var types []string
+	var items []string
+	cursor := 0
+	offered := make(map[string]int)
+	for _, option := range options {
+		next, err := r.version.Bump(option.versionType, r.cfg.Preid)
+		if err != nil {
+			continue
+		}
+		// Under CalVer several types lead to the same version; offer it once
+		if index, ok := offered[next.String()]; ok {
+			if option.versionType == suggested {
+				cursor = index
+				items[index] += " [suggested]"
+			}
+			continue
+		}
+		offered[next.String()] = len(items)
+
+		item := fmt.Sprintf("%s (%s) - %s", option.versionType, r.tagName(next), option.description)
+		if option.versionType == suggested {
+			cursor = len(items)
+			item += " [suggested]"
+		}
+		types = append(types, option.versionType)
+		items = append(items, item)
 	}
+
+	prompt := promptui.Select{
+		Label:     "Select version type",
+		Items:     items,
+		Size:      len(items),
+		CursorPos: cursor,
+	}
+
+	index, _, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+
+	return types[index], nil
 }
 
-func (r *Release) promptReleaseMessage(version string) (string, error) {
+func (r *Release) promptReleaseMessage(defaultMessage string) (string, error) {
+	// Multi-line messages cannot be edited in a single-line prompt, so show
+	// them and offer to replace them instead.
+	if strings.Contains(strings.TrimSpace(defaultMessage), "\n") {
+		printInfo("Release message:")
+		fmt.Println(defaultMessage)
+		if r.confirmProceed("Use this release message?") {
+			return defaultMessage, nil
+		}
+		defaultMessage = ""
+	}
+
 	prompt := promptui.Prompt{
 		Label:   "Release message",
-		Default: fmt.Sprintf("Release %s", version),
+		Default: defaultMessage,
 	}
 
 	return prompt.Run()
 }
 
 func (r *Release) confirmProceed(message string) bool {
-	prompt := promptui.Prompt{
-		Label:     message,
-		IsConfirm: true,
-	}
-
-	result, err := prompt.Run()
-	return err == nil && (result == "y" || result == "yes")
+	return promptConfirm(message)
 }
 
-func (r *Release) runPreReleaseChecks() error {
+func (r *Release) runPreReleaseChecks(ctx context.Context) error {
+	if r.cfg.SkipChecks {
+		printWarning("⚠️  Skipping pre-release checks")
+		return nil
+	}
+
 	printInfo("Running pre-release checks...")
 
 	checker := NewChecker(r.cfg)
-	if err := checker.RunAll(); err != nil {
+	if err := checker.RunAll(ctx); err != nil {
 		return fmt.Errorf("pre-release checks failed: %w", err)
 	}
 
-	printSuccess("✅ All checks passed")
+	// Only claim success when a check actually ran
+	for _, result := range checker.results {
+		if result.Status != CheckSkipped {
+			printSuccess("✅ All required checks passed")
+			return nil
+		}
+	}
+	if len(checker.results) > 0 {
+		printWarning("⚠️  No check ran")
+	}
 	return nil
 }
 
-func (r *Release) createAndPushTag(tag, message string) error {
-	printInfo(fmt.Sprintf("Creating tag %s...", tag))
-	if err := r.git.CreateTag(tag, message); err != nil {
-		return err
+// pushRelease pushes the tag in one atomic push with the branch of the
+// release when it has a release commit and with the release branch when
+// one is to be pushed.
+func (r *Release) pushRelease(ctx context.Context, tx *transaction) error {
+	var refs []string
+	if tx.Committed {
+		if tx.Branch == "" {
+			printWarning("⚠️  HEAD is not on a branch, so the release commit is only pushed with the tag")
+		} else {
+			refs = append(refs, "refs/heads/"+tx.Branch)
+		}
+	}
+	if tx.PushBranch != "" && (len(refs) == 0 || refs[0] != "refs/heads/"+tx.PushBranch) {
+		refs = append(refs, "refs/heads/"+tx.PushBranch)
 	}
+	refs = append(refs, "refs/tags/"+tx.Tag)
 
-	printInfo(fmt.Sprintf("Pushing tag %s...", tag))
-	if err := r.git.PushTag(tag); err != nil {
-		return err
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, git.ShortRef(ref))
 	}
+	printInfo(fmt.Sprintf("Pushing %s to %s...", strings.Join(names, ", "), r.cfg.Remote))
 
-	printSuccess(fmt.Sprintf("✅ Successfully created and pushed tag %s", tag))
-	
-	// Handle branch creation based on configuration
-	if r.cfg.NoBranch {
-		// Skip branch creation entirely when nobranch flag is set
-		printInfo("Skipping branch creation (--nobranch flag set)")
-	} else if r.cfg.CreateBranch {
-		// Non-interactive mode with CLI arguments
-		if err := r.handleBranchCreationNonInteractive(tag); err != nil {
-			printError(fmt.Sprintf("Failed to create/manage branch: %v", err))
-		}
-	} else {
-		// Interactive mode - ask if user wants to create a branch
-		if r.confirmProceed("Do you want to create a branch for this tag?") {
-			if err := r.handleBranchCreation(tag); err != nil {
-				printError(fmt.Sprintf("Failed to create/manage branch: %v", err))
+	if err := r.git.Push(ctx, refs...); err != nil {
+		var pushErr *git.PushError
+		if errors.As(err, &pushErr) {
+			for _, rejected := range pushErr.Rejected {
+				printError(fmt.Sprintf("❌ %s rejected by %s: %s", git.ShortRef(rejected.Ref), r.cfg.Remote, rejected.Reason))
 			}
 		}
+		return err
 	}
-	
+
+	printSuccess(fmt.Sprintf("✅ Successfully created tag %s and pushed %s", tx.Tag, strings.Join(names, ", ")))
 	printInfo("GitHub Actions should now trigger the release workflow")
 	return nil
 }
 
-func (r *Release) handleBranchCreation(tag string) error {
+// releaseBranch creates or updates the release branch for tag as
+// configured, or as chosen in interactive mode, and returns it when it is
+// to be pushed.
+func (r *Release) releaseBranch(ctx context.Context, tx *transaction, tag string) (string, error) {
+	var branch string
+	var err error
+	switch {
+	case r.cfg.NoBranch:
+		printInfo("Skipping branch creation (--nobranch flag set)")
+	case r.cfg.CreateBranch:
+		branch, err = r.handleBranchCreationNonInteractive(ctx, tx, tag)
+	case r.confirmProceed("Do you want to create a branch for this tag?"):
+		branch, err = r.handleBranchCreation(ctx, tx, tag)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to create/manage branch: %w", err)
+	}
+	return branch, nil
+}
+
+// handleBranchCreation creates or merges into the release branch as chosen
+// in the prompts and returns it when it is to be pushed with the tag.
+func (r *Release) handleBranchCreation(ctx context.Context, tx *transaction, tag string) (string, error) {
 	// Remember the current branch to return to it later
-	originalBranch, err := r.git.GetCurrentBranch()
+	originalBranch, err := r.git.GetCurrentBranch(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get current branch: %w", err)
+		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
+	tx.record(change{Kind: changeCheckout, Ref: originalBranch})
 	
 	// Ensure we return to the original branch at the end
 	defer func() {
-		if err := r.git.CheckoutBranch(originalBranch); err != nil {
+		// Return even when interrupted
+		if err := r.git.CheckoutBranch(context.WithoutCancel(ctx), originalBranch); err != nil {
 			printError(fmt.Sprintf("Failed to return to original branch %s: %v", originalBranch, err))
 		} else {
 			printInfo(fmt.Sprintf("Returned to branch %s", originalBranch))
@@ -229,48 +665,57 @@ func (r *Release) handleBranchCreation(tag string) error {
 	}()
 	
 	// Get source branch
-	defaultBranch, err := r.git.GetDefaultBranch()
+	defaultBranch, err := r.git.GetDefaultBranch(ctx)
 	if err != nil {
 		defaultBranch = "main"
 	}
 	
 	sourceBranch, err := r.promptSourceBranch(defaultBranch)
 	if err != nil {
-		return err
+		return "", err
 	}
 	
 	// Get target branch name
 	targetBranch, err := r.promptTargetBranch(tag)
 	if err != nil {
-		return err
+		return "", err
 	}
 	
 	// Check if branch exists
-	if r.git.BranchExists(targetBranch) {
+	if r.git.BranchExists(ctx, targetBranch) {
 		printWarning(fmt.Sprintf("Branch %s already exists", targetBranch))
 		if r.confirmProceed(fmt.Sprintf("Do you want to merge %s into %s?", sourceBranch, targetBranch)) {
-			if err := r.git.MergeBranch(sourceBranch, targetBranch); err != nil {
-				return err
+			if err := r.mergeBranch(ctx, tx, sourceBranch, targetBranch); err != nil {
+				return "", err
 			}
 			printSuccess(fmt.Sprintf("✅ Successfully merged %s into %s", sourceBranch, targetBranch))
 		}
 	} else {
 		// Create new branch
-		if err := r.git.CreateBranch(targetBranch, sourceBranch); err != nil {
-			return err
+		tx.record(change{Kind: changeBranch, Ref: targetBranch})
+		if err := r.git.CreateBranch(ctx, targetBranch, sourceBranch); err != nil {
+			return "", err
 		}
 		printSuccess(fmt.Sprintf("✅ Successfully created branch %s from %s", targetBranch, sourceBranch))
 	}
 	
 	// Ask if user wants to push the branch
-	if r.confirmProceed(fmt.Sprintf("Do you want to push branch %s to origin?", targetBranch)) {
-		if err := r.git.PushBranch(targetBranch); err != nil {
-			return err
-		}
-		printSuccess(fmt.Sprintf("✅ Successfully pushed branch %s", targetBranch))
+	if r.confirmProceed(fmt.Sprintf("Do you want to push branch %s to %s?", targetBranch, r.cfg.Remote)) {
+		return targetBranch, nil
 	}
 	
-	return nil
+	return "", nil
+}
+
+// mergeBranch merges sourceBranch into the existing targetBranch,
+// recording where targetBranch pointed before.
+func (r *Release) mergeBranch(ctx context.Context, tx *transaction, sourceBranch, targetBranch string) error {
+	previous, err := r.git.ResolveRef(ctx, "refs/heads/"+targetBranch)
+	if err != nil {
+		return err
+	}
+	tx.record(change{Kind: changeMerge, Ref: targetBranch, Commit: previous})
+	return r.git.MergeBranch(ctx, sourceBranch, targetBranch)
 }
 
 func (r *Release) promptSourceBranch(defaultBranch string) (string, error) {
@@ -283,8 +728,7 @@ func (r *Release) promptSourceBranch(defaultBranch string) (string, error) {
 }
 
 func (r *Release) promptTargetBranch(defaultName string) (string, error) {
-	// Remove 'v' prefix from default branch name if present
-	defaultName = strings.TrimPrefix(defaultName, "v")
+	defaultName = r.branchName(defaultName)
 	
 	prompt := promptui.Prompt{
 		Label:   "Target branch name",
@@ -294,16 +738,20 @@ func (r *Release) promptTargetBranch(defaultName string) (string, error) {
 	return prompt.Run()
 }
 
-func (r *Release) handleBranchCreationNonInteractive(tag string) error {
+// handleBranchCreationNonInteractive creates or merges into the release
+// branch as configured and returns it when it is to be pushed with the tag.
+func (r *Release) handleBranchCreationNonInteractive(ctx context.Context, tx *transaction, tag string) (string, error) {
 	// Remember the current branch to return to it later
-	originalBranch, err := r.git.GetCurrentBranch()
+	originalBranch, err := r.git.GetCurrentBranch(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get current branch: %w", err)
+		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
+	tx.record(change{Kind: changeCheckout, Ref: originalBranch})
 	
 	// Ensure we return to the original branch at the end
 	defer func() {
-		if err := r.git.CheckoutBranch(originalBranch); err != nil {
+		// Return even when interrupted
+		if err := r.git.CheckoutBranch(context.WithoutCancel(ctx), originalBranch); err != nil {
 			printError(fmt.Sprintf("Failed to return to original branch %s: %v", originalBranch, err))
 		} else {
 			printInfo(fmt.Sprintf("Returned to branch %s", originalBranch))
@@ -313,7 +761,7 @@ func (r *Release) handleBranchCreationNonInteractive(tag string) error {
 	// Get source branch from config or default
 	sourceBranch := r.cfg.SourceBranch
 	if sourceBranch == "" {
-		defaultBranch, err := r.git.GetDefaultBranch()
+		defaultBranch, err := r.git.GetDefaultBranch(ctx)
 		if err != nil {
 			sourceBranch = "main"
 		} else {
@@ -321,23 +769,21 @@ func (r *Release) handleBranchCreationNonInteractive(tag string) error {
 		}
 	}
 	
-	// Get target branch name from config or use tag without 'v' prefix
+	// Get target branch name from config or use tag without its prefix
 	targetBranch := r.cfg.BranchName
 	if targetBranch == "" {
-		targetBranch = tag
-		// Remove 'v' prefix from tag for branch name
-		targetBranch = strings.TrimPrefix(targetBranch, "v")
+		targetBranch = r.branchName(tag)
 	}
 	
 	printInfo(fmt.Sprintf("Creating branch %s from %s...", targetBranch, sourceBranch))
 	
 	// Check if branch exists
-	if r.git.BranchExists(targetBranch) {
+	if r.git.BranchExists(ctx, targetBranch) {
 		printWarning(fmt.Sprintf("Branch %s already exists", targetBranch))
 		if r.cfg.AutoMerge {
 			printInfo(fmt.Sprintf("Auto-merging %s into %s...", sourceBranch, targetBranch))
-			if err := r.git.MergeBranch(sourceBranch, targetBranch); err != nil {
-				return err
+			if err := r.mergeBranch(ctx, tx, sourceBranch, targetBranch); err != nil {
+				return "", err
 			}
 			printSuccess(fmt.Sprintf("✅ Successfully merged %s into %s", sourceBranch, targetBranch))
 		} else {
@@ -345,28 +791,24 @@ func (r *Release) handleBranchCreationNonInteractive(tag string) error {
 		}
 	} else {
 		// Create new branch
-		if err := r.git.CreateBranch(targetBranch, sourceBranch); err != nil {
-			return err
+		tx.record(change{Kind: changeBranch, Ref: targetBranch})
+		if err := r.git.CreateBranch(ctx, targetBranch, sourceBranch); err != nil {
+			return "", err
 		}
 		printSuccess(fmt.Sprintf("✅ Successfully created branch %s from %s", targetBranch, sourceBranch))
 	}
 	
-	// Push branch if auto-push is enabled
+	// Push branch with the tag if auto-push is enabled
 	if r.cfg.AutoPush {
-		printInfo(fmt.Sprintf("Pushing branch %s to origin...", targetBranch))
-		if err := r.git.PushBranch(targetBranch); err != nil {
-			return err
-		}
-		printSuccess(fmt.Sprintf("✅ Successfully pushed branch %s", targetBranch))
-	} else {
-		printInfo("Branch not pushed (use --auto-push to push automatically)")
+		return targetBranch, nil
 	}
+	printInfo("Branch not pushed (use --auto-push to push automatically)")
 	
-	return nil
+	return "", nil
 }
 
-func (r *Release) ListTags() error {
-	tags, err := r.git.GetAllTags()
+func (r *Release) ListTags(ctx context.Context, sortBy string) error {
+	tags, err := r.git.GetAllTags(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get tags: %w", err)
 	}
@@ -376,7 +818,15 @@ func (r *Release) ListTags() error {
 		return nil
 	}
 	
-	printInfo(fmt.Sprintf("Found %d tags (sorted by creation date, newest first):\n", len(tags)))
+	switch sortBy {
+	case "", "date":
+		printInfo(fmt.Sprintf("Found %d tags (sorted by creation date, newest first):\n", len(tags)))
+	case "version":
+		tags = sortTagsByVersion(tags, r.cfg.VersionTagFormat())
+		printInfo(fmt.Sprintf("Found %d tags (sorted by version, highest first):\n", len(tags)))
+	default:
+		return fmt.Errorf("invalid sort order: %s (must be date or version)", sortBy)
+	}
 	
 	for _, tag := range tags {
 		fmt.Println(tag)
@@ -385,14 +835,89 @@ func (r *Release) ListTags() error {
 	return nil
 }
 
-func GetCurrentVersion() string {
-	cfg := config.New()
+// sortTagsByVersion orders "<tag> <date>" lines by descending version
+// precedence. Tags that are not valid versions keep their relative order and
+// are listed after all versions.
+func sortTagsByVersion(lines []string, format version.TagFormat) []string {
+	var versions []*version.Version
+	var others []string
+	byTag := make(map[string]string)
+	// Collect the versions backwards, so that reversing the ascending
+	// stable sort keeps tags of equal precedence in their original order
+	for i := len(lines) - 1; i >= 0; i-- {
+		name := strings.Fields(lines[i])[0]
+		if v, err := format.Parse(name); err == nil {
+			versions = append(versions, v)
+			byTag[v.Raw] = lines[i]
+		} else {
+			others = append([]string{lines[i]}, others...)
+		}
+	}
+
+	version.Sort(versions)
+
+	sorted := make([]string, 0, len(lines))
+	for i := len(versions) - 1; i >= 0; i-- {
+		sorted = append(sorted, byTag[versions[i].Raw])
+	}
+	return append(sorted, others...)
+}
+
+func GetCurrentVersion(ctx context.Context, cfg *config.Config) string {
 	gitClient := git.NewClient(cfg)
-	version, err := gitClient.GetLatestTag()
+	tag, err := gitClient.GetLatestTag(ctx)
+	if err != nil {
+		return cfg.VersionTagFormat().Tag(version.Zero(cfg.VersionScheme()))
+	}
+	return tag
+}
+
+// tagName returns the tag for v in the configured tag format, preceded by
+// the module directory when a module is selected.
+func (r *Release) tagName(v *version.Version) string {
+	return r.cfg.VersionTagFormat().Tag(v)
+}
+
+// branchName returns the default release branch name for tag: its version
+// without the prefix and suffix of the tag format, under the module
+// directory when a module is selected.
+func (r *Release) branchName(tag string) string {
+	name := tag
+	if v, err := r.cfg.VersionTagFormat().Parse(tag); err == nil {
+		name = v.String()
+	}
+	if dir := r.cfg.ModuleDir(); dir != "" {
+		return dir + "/" + name
+	}
+	return name
+}
+
+// moduleRoot returns the directory of the selected module, or the
+// repository root when no module is selected.
+func (r *Release) moduleRoot(ctx context.Context) (string, error) {
+	root, err := r.git.GetRepoRoot(ctx)
 	if err != nil {
-		return "v0.0.0"
+		return "", err
+	}
+	return filepath.Join(root, filepath.FromSlash(r.cfg.ModuleDir())), nil
+}
+
+// changelogFile returns the changelog to update.
+func (r *Release) changelogFile(ctx context.Context) (string, error) {
+	return r.modulePath(ctx, r.cfg.ChangelogFile)
+}
+
+// modulePath resolves a relative path in the module directory when a
+// module is selected.
+func (r *Release) modulePath(ctx context.Context, path string) (string, error) {
+	if r.cfg.ModuleDir() == "" || filepath.IsAbs(path) {
+		return path, nil
+	}
+	dir, err := r.moduleRoot(ctx)
+	if err != nil {
+		return "", err
 	}
-	return version
+	return filepath.Join(dir, path), nil
 }
 
 func printInfo(message string) {
@@ -0,0 +1,188 @@
+package bump
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// changeKind is the kind of change a release step made to the repository.
+type changeKind string
+
+const (
+	// changeCommit is the release commit; undone by resetting Ref to Commit
+	changeCommit changeKind = "commit"
+	// changeTag is the local tag Ref; undone by deleting it
+	changeTag changeKind = "tag"
+	// changeBranch is the branch Ref created for the release; undone by
+	// deleting it
+	changeBranch changeKind = "branch"
+	// changeMerge is a merge into the branch Ref; undone by resetting it to
+	// Commit
+	changeMerge changeKind = "merge"
+	// changeCheckout is leaving the branch Ref; undone by returning to it
+	changeCheckout changeKind = "checkout"
+	// changeFile is a write to the file Ref; undone by restoring Content,
+	// or removing the file when Content is nil
+	changeFile changeKind = "file"
+)
+
+// change records something a release step changed in the repository and
+// what was there before, so it can be undone when a later step fails.
+type change struct {
+	Step    string     `json:"step"`
+	Kind    changeKind `json:"kind"`
+	Ref     string     `json:"ref"`
+	Commit  string     `json:"commit,omitempty"`
+	Content []byte     `json:"content"`
+}
+
+// rollback undoes the changes of the release in reverse order, printing
+// each one, and returns err. A change that cannot be undone is reported
+// and left in the journal for bump abort, and the remaining changes are
+// still undone.
+func (r *Release) rollback(ctx context.Context, tx *transaction, err error) error {
+	if len(tx.Changes) == 0 {
+		tx.remove()
+		return err
+	}
+
+	// Roll back even when interrupted
+	ctx = context.WithoutCancel(ctx)
+
+	printWarning(fmt.Sprintf("Rolling back the release: %v", err))
+	if r.undoChanges(ctx, tx, "") {
+		tx.remove()
+	} else {
+		tx.RolledBack = true
+		tx.save()
+		printWarning("Run 'bump abort' to retry undoing the remaining changes")
+	}
+
+	return err
+}
+
+// undoChanges undoes the changes made by step, or all changes when step is
+// empty, in reverse order and removes them from the journal. It reports
+// whether every change could be undone.
+func (r *Release) undoChanges(ctx context.Context, tx *transaction, step string) bool {
+	ok := true
+	var kept []change
+	for i := len(tx.Changes) - 1; i >= 0; i-- {
+		c := tx.Changes[i]
+		if step != "" && c.Step != step {
+			kept = append([]change{c}, kept...)
+			continue
+		}
+		undone, err := r.undo(ctx, c)
+		switch {
+		case err != nil:
+			printError(fmt.Sprintf("❌ Failed to %s: %v", c.describe(false), err))
+			kept = append([]change{c}, kept...)
+			ok = false
+		case undone:
+			printWarning(fmt.Sprintf("↩️  %s", c.describe(true)))
+		}
+	}
+	tx.Changes = kept
+	tx.save()
+
+	return ok
+}
+
+// undo reverts c and reports whether there was anything to revert.
+func (r *Release) undo(ctx context.Context, c change) (bool, error) {
+	switch c.Kind {
+	case changeCommit, changeMerge:
+		ref := "HEAD"
+		if c.Ref != "" {
+			ref = "refs/heads/" + c.Ref
+		}
+		if current, err := r.git.ResolveRef(ctx, ref); err == nil && current == c.Commit {
+			return false, nil
+		}
+		return true, r.git.ResetBranch(ctx, c.Ref, c.Commit)
+	case changeTag:
+		if !r.git.TagExists(ctx, c.Ref) {
+			return false, nil
+		}
+		return true, r.git.DeleteTag(ctx, c.Ref)
+	case changeBranch:
+		if !r.git.BranchExists(ctx, "refs/heads/"+c.Ref) {
+			return false, nil
+		}
+		return true, r.git.DeleteBranch(ctx, c.Ref)
+	case changeCheckout:
+		current, err := r.git.GetCurrentBranch(ctx)
+		if c.Ref == "" || (err == nil && current == c.Ref) {
+			return false, nil
+		}
+		return true, r.git.CheckoutBranch(ctx, c.Ref)
+	case changeFile:
+		current, err := os.ReadFile(c.Ref) // #nosec G304 -- files of the release
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, err
+		}
+		if (err == nil) == (c.Content != nil) && string(current) == string(c.Content) {
+			return false, nil
+		}
+		if c.Content == nil {
+			if err := os.Remove(c.Ref); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return false, err
+			}
+			return true, nil
+		}
+		return true, writeReleaseFile(c.Ref, c.Content)
+	}
+
+	return false, fmt.Errorf("unknown change %q", c.Kind)
+}
+
+// describe says what undoing c does, or did once done.
+func (c change) describe(done bool) string {
+	var verb, object string
+	switch c.Kind {
+	case changeCommit:
+		ref := c.Ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		verb, object = "reset", fmt.Sprintf("%s to %s, removing the release commit", ref, shortCommit(c.Commit))
+	case changeTag:
+		verb, object = "delete", fmt.Sprintf("local tag %s", c.Ref)
+	case changeBranch:
+		verb, object = "delete", fmt.Sprintf("branch %s", c.Ref)
+	case changeMerge:
+		verb, object = "reset", fmt.Sprintf("branch %s to %s, removing the merge", c.Ref, shortCommit(c.Commit))
+	case changeCheckout:
+		verb, object = "return", fmt.Sprintf("to branch %s", c.Ref)
+	case changeFile:
+		verb, object = "restore", c.Ref
+		if c.Content == nil {
+			verb = "remove"
+		}
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, c.Ref); err == nil && !strings.HasPrefix(rel, "..") {
+				object = rel
+			}
+		}
+	default:
+		return fmt.Sprintf("undo unknown change %q", c.Kind)
+	}
+
+	if done {
+		past := map[string]string{"reset": "Reset", "delete": "Deleted", "return": "Returned", "restore": "Restored", "remove": "Removed"}
+		verb = past[verb]
+	}
+	return verb + " " + object
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
@@ -0,0 +1,93 @@
+package bump
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ypeckstadt/bump/internal/config"
+	"github.com/ypeckstadt/bump/internal/git"
+	"github.com/ypeckstadt/bump/internal/version"
+)
+
+// ShowStatus prints the current version and, in a monorepo, the version of
+// every module. Modules are the directories with version tags under their
+// path and those holding a detected project.
+func ShowStatus(ctx context.Context, cfg *config.Config) error {
+	if dir := cfg.ModuleDir(); dir != "" {
+		fmt.Printf("Current version of %s: %s\n", dir, GetCurrentVersion(ctx, cfg))
+		return nil
+	}
+	fmt.Printf("Current repository version: %s\n", GetCurrentVersion(ctx, cfg))
+
+	gitClient := git.NewClient(cfg)
+	tags, err := gitClient.ListTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	modules := make(map[string]bool)
+	for _, tag := range tags {
+		if dir, ok := moduleOfTag(tag, cfg.TagFormatFor("")); ok {
+			modules[dir] = true
+		}
+	}
+	if root, err := gitClient.GetRepoRoot(ctx); err == nil {
+		if projects, err := detectProjects(root); err == nil {
+			for _, p := range projects {
+				if p.dir != "." {
+					modules[p.dir] = true
+				}
+			}
+		}
+	}
+	if len(modules) == 0 {
+		return nil
+	}
+
+	dirs := make([]string, 0, len(modules))
+	for dir := range modules {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tVERSION")
+	fmt.Fprintf(w, ".\t%s\n", latestTag(tags, cfg.TagFormatFor("")))
+	for _, dir := range dirs {
+		fmt.Fprintf(w, "%s\t%s\n", dir, latestTag(tags, cfg.TagFormatFor(dir)))
+	}
+	return w.Flush()
+}
+
+// moduleOfTag returns the module directory of a tag such as
+// services/api/v1.2.3.
+func moduleOfTag(tag string, format version.TagFormat) (string, bool) {
+	for i := strings.LastIndex(tag, "/"); i > 0; i = strings.LastIndex(tag[:i], "/") {
+		if _, err := format.Parse(tag[i+1:]); err == nil {
+			return tag[:i], true
+		}
+	}
+	return "", false
+}
+
+// latestTag returns the tag with the highest version among the tags in
+// format, or "-" when there is none.
+func latestTag(tags []string, format version.TagFormat) string {
+	var versions []*version.Version
+	for _, tag := range tags {
+		if v, err := format.Parse(tag); err == nil {
+			versions = append(versions, v)
+		}
+	}
+
+	latest := version.Latest(versions)
+	if latest == nil {
+		return "-"
+	}
+	return latest.Raw
+}
@@ -0,0 +1,54 @@
+package bump
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ypeckstadt/bump/internal/version"
+)
+
+// stageVersionFiles adds the configured version files, with newVersion
+// written to them, to the release commit. Every file is checked before the
+// release commit writes any of them, and a file to update with local changes
+// refuses the release, as the release commit would include those changes.
+func (r *Release) stageVersionFiles(ctx context.Context, files *releaseFiles, newVersion *version.Version) error {
+	var modified []string
+	for _, entry := range r.cfg.VersionFiles {
+		path, err := r.modulePath(ctx, entry.Path)
+		if err != nil {
+			return err
+		}
+		file := entry.File(path)
+
+		content, err := files.read(path)
+		if err != nil {
+			return fmt.Errorf("failed to read version file: %w", err)
+		}
+		replaced, old, err := file.Replace(content, newVersion.String())
+		if err != nil {
+			return fmt.Errorf("failed to update version file %s: %w", entry.Path, err)
+		}
+		if string(replaced) == string(content) {
+			continue
+		}
+		changed, err := r.git.HasLocalChanges(ctx, path)
+		if err != nil {
+			return err
+		}
+		if changed {
+			modified = append(modified, entry.Path)
+			continue
+		}
+
+		if err := files.set(path, replaced); err != nil {
+			return err
+		}
+		printInfo(fmt.Sprintf("Updating %s: %s → %s", entry.Path, old, newVersion.String()))
+	}
+
+	if len(modified) > 0 {
+		return fmt.Errorf("version files have local changes, commit or stash them first: %s", strings.Join(modified, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ypeckstadt/bump/internal/conventional"
+	"github.com/ypeckstadt/bump/internal/version"
+)
+
+// linkReference matches a Markdown link reference definition such as
+// "[1.2.0]: https://github.com/org/repo/compare/v1.1.0...v1.2.0".
+var linkReference = regexp.MustCompile(`^\[[^\]]+\]:\s`)
+
+// Header returns the header of a new changelog, naming the versioning
+// scheme the project follows.
+func Header(scheme version.Scheme) string {
+	versioning := "and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html)."
+	if calver, ok := scheme.(*version.CalVer); ok {
+		versioning = fmt.Sprintf("and this project uses [Calendar Versioning](https://calver.org/) in the format %s.", calver)
+	}
+
+	return `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+` + versioning + "\n"
+}
+
+// Group is a titled list of changelog entries.
+type Group struct {
+	Title   string
+	Commits []conventional.Commit
+}
+
+// GroupCommits sorts commits into Breaking Changes, Features, Fixes and
+// Other, omitting empty groups.
+func GroupCommits(commits []conventional.Commit) []Group {
+	groups := []Group{
+		{Title: "Breaking Changes"},
+		{Title: "Features"},
+		{Title: "Fixes"},
+		{Title: "Other"},
+	}
+
+	for _, commit := range commits {
+		switch {
+		case commit.Breaking:
+			groups[0].Commits = append(groups[0].Commits, commit)
+		case commit.Type == "feat":
+			groups[1].Commits = append(groups[1].Commits, commit)
+		case commit.Type == "fix" || commit.Type == "perf":
+			groups[2].Commits = append(groups[2].Commits, commit)
+		default:
+			groups[3].Commits = append(groups[3].Commits, commit)
+		}
+	}
+
+	var nonEmpty []Group
+	for _, group := range groups {
+		if len(group.Commits) > 0 {
+			nonEmpty = append(nonEmpty, group)
+		}
+	}
+	return nonEmpty
+}
+
+// Render renders the changelog section for a release.
+func Render(version string, date time.Time, commits []conventional.Commit) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## [%s] - %s\n", version, date.Format("2006-01-02"))
+
+	for _, group := range GroupCommits(commits) {
+		fmt.Fprintf(&b, "\n### %s\n\n", group.Title)
+		for _, commit := range group.Commits {
+			b.WriteString("- " + entry(commit, group.Title == "Breaking Changes") + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// Prepend inserts section into the changelog at path above the most recent
+// release, keeping an [Unreleased] section at the top and moving its entries
+// into the new section. The file is created with header when it does not
+// exist.
+func Prepend(path, version, section, header string) error {
+	content, err := os.ReadFile(path) // #nosec G304 -- path is provided by the user
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	updated, err := Insert(string(content), version, section, header)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", path, err)
+	}
+
+	// #nosec G306 -- changelogs are meant to be world readable
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Insert returns the changelog content with section inserted like Prepend
+// does. Empty content starts a new changelog with header.
+func Insert(content, version, section, header string) (string, error) {
+	if content == "" {
+		content = header
+	}
+	return insertSection(content, version, section)
+}
+
+// insertSection inserts section above the most recent release. The entries
+// of an [Unreleased] section above it are released with section, leaving
+// the [Unreleased] heading empty.
+func insertSection(content, version, section string) (string, error) {
+	lines := strings.Split(content, "\n")
+
+	insertAt, unreleased := -1, -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if strings.HasPrefix(line, fmt.Sprintf("## [%s]", version)) {
+			return "", fmt.Errorf("a section for %s already exists", version)
+		}
+		if strings.HasPrefix(strings.ToLower(line), "## [unreleased]") {
+			if unreleased == -1 && insertAt == -1 {
+				unreleased = i
+			}
+			continue
+		}
+		if insertAt == -1 {
+			insertAt = i
+		}
+	}
+
+	if unreleased != -1 {
+		end := insertAt
+		if end == -1 {
+			// Keep the link reference definitions at the end of the file
+			end = len(lines)
+			for end > unreleased+1 && (strings.TrimSpace(lines[end-1]) == "" || linkReference.MatchString(lines[end-1])) {
+				end--
+			}
+		}
+		if entries := strings.TrimSpace(strings.Join(lines[unreleased+1:end], "\n")); entries != "" {
+			section = strings.TrimRight(section, "\n") + "\n\n" + entries + "\n"
+		}
+
+		before := strings.Join(lines[:unreleased+1], "\n")
+		after := strings.TrimLeft(strings.Join(lines[end:], "\n"), "\n")
+		if after == "" {
+			return before + "\n\n" + section, nil
+		}
+		return before + "\n\n" + section + "\n" + after, nil
+	}
+
+	if insertAt == -1 {
+		return strings.TrimRight(content, "\n") + "\n\n" + section, nil
+	}
+
+	before := strings.TrimRight(strings.Join(lines[:insertAt], "\n"), "\n")
+	after := strings.Join(lines[insertAt:], "\n")
+	return before + "\n\n" + section + "\n" + after, nil
+}
+
+func entry(commit conventional.Commit, breaking bool) string {
+	description := commit.Description
+	if breaking && commit.BreakingNote != "" {
+		description = commit.BreakingNote
+	}
+	if commit.Scope != "" {
+		description = fmt.Sprintf("**%s:** %s", commit.Scope, description)
+	}
+	if commit.Hash != "" {
+		description = fmt.Sprintf("%s (%s)", description, commit.Hash)
+	}
+	return description
+}
@@ -0,0 +1,148 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ypeckstadt/bump/internal/conventional"
+	"github.com/ypeckstadt/bump/internal/version"
+)
+
+const section = "## [1.2.0] - 2026-10-17\n\n### Features\n\n- add Push (abc1234)\n"
+
+func TestInsertSection(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "above the latest release",
+			content: "# Changelog\n\nIntro.\n\n## [1.1.0] - 2026-09-01\n\n- old\n",
+			want:    "# Changelog\n\nIntro.\n\n" + section + "\n## [1.1.0] - 2026-09-01\n\n- old\n",
+		},
+		{
+			name:    "without releases",
+			content: "# Changelog\n\nIntro.\n",
+			want:    "# Changelog\n\nIntro.\n\n" + section,
+		},
+		{
+			name:    "below an empty unreleased section",
+			content: "# Changelog\n\n## [Unreleased]\n\n## [1.1.0] - 2026-09-01\n\n- old\n",
+			want:    "# Changelog\n\n## [Unreleased]\n\n" + section + "\n## [1.1.0] - 2026-09-01\n\n- old\n",
+		},
+		{
+			name:    "moves the unreleased entries",
+			content: "# Changelog\n\n## [Unreleased]\n\n### Security\n\n- rotate keys\n\n## [1.1.0] - 2026-09-01\n\n- old\n",
+			want:    "# Changelog\n\n## [Unreleased]\n\n" + section + "\n### Security\n\n- rotate keys\n\n## [1.1.0] - 2026-09-01\n\n- old\n",
+		},
+		{
+			name:    "moves the unreleased entries of the first release",
+			content: "# Changelog\n\n## [unreleased]\n- first\n",
+			want:    "# Changelog\n\n## [unreleased]\n\n" + section + "\n- first\n",
+		},
+		{
+			name:    "keeps link references at the end",
+			content: "# Changelog\n\n## [Unreleased]\n\n- first\n\n[unreleased]: https://example.com/compare/v1.1.0...HEAD\n",
+			want:    "# Changelog\n\n## [Unreleased]\n\n" + section + "\n- first\n\n[unreleased]: https://example.com/compare/v1.1.0...HEAD\n",
+		},
+		{
+			name:    "keeps an unreleased section below the latest release",
+			content: "# Changelog\n\n## [1.1.0] - 2026-09-01\n\n- old\n\n## [Unreleased]\n\n- stray\n",
+			want:    "# Changelog\n\n" + section + "\n## [1.1.0] - 2026-09-01\n\n- old\n\n## [Unreleased]\n\n- stray\n",
+		},
+		{
+			name:    "rejects an existing section",
+			content: "# Changelog\n\n## [1.2.0] - 2026-10-01\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := insertSection(tt.content, "1.2.0", section)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("insertSection() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("insertSection() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("insertSection() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertNewChangelog(t *testing.T) {
+	calver, err := version.NewCalVer("YYYY.0M.MICRO")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		scheme version.Scheme
+		want   string
+	}{
+		{name: "semver", scheme: version.SemVer{}, want: "adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html)."},
+		{name: "calver", scheme: calver, want: "uses [Calendar Versioning](https://calver.org/) in the format YYYY.0M.MICRO."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Insert("", "1.2.0", section, Header(tt.scheme))
+			if err != nil {
+				t.Fatalf("Insert() failed: %v", err)
+			}
+			if !strings.HasPrefix(got, "# Changelog\n") || !strings.HasSuffix(got, "\n\n"+section) {
+				t.Errorf("Insert() =\n%s\nwant the header followed by the section", got)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Insert() =\n%s\nwant it to contain %q", got, tt.want)
+			}
+			if tt.name == "calver" && strings.Contains(got, "Semantic Versioning") {
+				t.Errorf("Insert() claims Semantic Versioning for CalVer:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	commits := []conventional.Commit{
+		conventional.Parse("a1", "feat(api): add Push", ""),
+		conventional.Parse("b2", "fix: handle empty tags", ""),
+		conventional.Parse("c3", "chore: bump deps", ""),
+		conventional.Parse("d4", "feat!: drop Go 1.20", "BREAKING CHANGE: Go 1.21 is required"),
+		conventional.Parse("e5", "perf: faster parsing", ""),
+	}
+
+	got := Render("1.2.0", time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC), commits)
+	want := `## [1.2.0] - 2026-10-17
+
+### Breaking Changes
+
+- Go 1.21 is required (d4)
+
+### Features
+
+- **api:** add Push (a1)
+
+### Fixes
+
+- handle empty tags (b2)
+- faster parsing (e5)
+
+### Other
+
+- bump deps (c3)
+`
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
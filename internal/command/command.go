@@ -0,0 +1,47 @@
+// Package command creates subprocesses that are stopped cleanly when their
+// context is cancelled.
+package command
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// GracePeriod is how long a cancelled process may take to exit after it has
+// been interrupted before it is killed.
+const GracePeriod = 5 * time.Second
+
+// New returns a command that is interrupted when ctx is done and killed if
+// it has not exited after GracePeriod.
+func New(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error {
+		return interrupt(cmd.Process)
+	}
+	cmd.WaitDelay = GracePeriod
+	return cmd
+}
+
+// NewGroup is like New but runs the command in its own process group and
+// interrupts the whole group, so processes started by a shell are stopped
+// too. Processes in their own group cannot read from the terminal, so this
+// is meant for non-interactive commands.
+func NewGroup(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		return interruptGroup(cmd.Process)
+	}
+	cmd.WaitDelay = GracePeriod
+	return cmd
+}
+
+func interrupt(process *os.Process) error {
+	if err := process.Signal(os.Interrupt); err != nil {
+		// Interrupting is not supported on every platform
+		return process.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package command
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func interruptGroup(process *os.Process) error {
+	// A negative pid signals every process in the group
+	if err := syscall.Kill(-process.Pid, syscall.SIGTERM); err != nil {
+		return process.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package command
+
+import (
+	"os"
+	"os/exec"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func interruptGroup(process *os.Process) error {
+	return process.Kill()
+}
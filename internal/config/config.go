@@ -1,14 +1,152 @@
 package config
 
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ypeckstadt/bump/internal/version"
+	"github.com/ypeckstadt/bump/internal/versionfile"
+)
+
+const (
+	// TagScopeReachable only considers tags reachable from HEAD when
+	// determining the current version.
+	TagScopeReachable = "reachable"
+	// TagScopeAll considers every tag in the repository.
+	TagScopeAll = "all"
+
+	// SeverityRequired checks block the release when they fail.
+	SeverityRequired = "required"
+	// SeverityAdvisory checks report failures without blocking the release.
+	SeverityAdvisory = "advisory"
+
+	// ModulePathBlock refuses a major release of a Go module whose module
+	// path lacks the new major version suffix.
+	ModulePathBlock = "block"
+	// ModulePathRewrite updates the module path and imports in a commit
+	// before tagging.
+	ModulePathRewrite = "rewrite"
+	// ModulePathIgnore tags without checking the module path.
+	ModulePathIgnore = "ignore"
+
+	// SchemeSemVer versions releases with Semantic Versioning.
+	SchemeSemVer = "semver"
+	// SchemeCalVer versions releases with calendar versioning in
+	// CalVerFormat.
+	SchemeCalVer = "calver"
+)
+
+// CheckConfig declares a pre-release check that runs a shell command.
+type CheckConfig struct {
+	Name string `yaml:"name" toml:"name"`
+	// Run is executed with sh -c (cmd /C on Windows).
+	Run string `yaml:"run" toml:"run"`
+	// Dir is the working directory, relative to where bump runs.
+	Dir string            `yaml:"dir" toml:"dir"`
+	Env map[string]string `yaml:"env" toml:"env"`
+	// Timeout is a duration such as "30s" or "5m"; empty means no timeout.
+	Timeout string `yaml:"timeout" toml:"timeout"`
+	// Severity is required (the default) or advisory.
+	Severity string `yaml:"severity" toml:"severity"`
+	// Order positions the check among the others. Built-in checks are
+	// numbered 10, 20, 30 and so on across all detected projects, in the
+	// order they are listed, so their orders depend on the repository.
+	// Checks without an order run last.
+	Order int `yaml:"order" toml:"order"`
+	// DependsOn names checks that must finish first. The check is skipped
+	// when one of them fails.
+	DependsOn []string `yaml:"depends_on" toml:"depends_on"`
+}
+
+// VersionFileConfig declares a file that records the version and is updated
+// on release.
+type VersionFileConfig struct {
+	// Path is relative to the module directory when a module is selected.
+	Path string `yaml:"path" toml:"path"`
+	// Format is json, yaml, toml, regex, plain or go. By default it follows
+	// the file extension, or is regex when Pattern is set.
+	Format string `yaml:"format" toml:"format"`
+	// Key is the dotted path of the version, such as package.version, or
+	// the name of a Go constant.
+	Key string `yaml:"key" toml:"key"`
+	// Pattern is a regular expression whose capture group is the version.
+	Pattern string `yaml:"pattern" toml:"pattern"`
+}
+
+// Config holds all options. The config tag is the key used in configuration
+// files; the matching environment variable is BUMP_ followed by the key in
+// upper case, and the matching flag is the key with dashes instead of
+// underscores.
 type Config struct {
-	DryRun       bool
-	Verbose      bool
-	NoBranch     bool
-	CreateBranch bool
-	SourceBranch string
-	BranchName   string
-	AutoMerge    bool
-	AutoPush     bool
+	DryRun       bool   `config:"dry_run"`
+	Verbose      bool   `config:"verbose"`
+	NoBranch     bool   `config:"nobranch"`
+	CreateBranch bool   `config:"create_branch"`
+	SourceBranch string `config:"source_branch"`
+	BranchName   string `config:"branch_name"`
+	AutoMerge    bool   `config:"auto_merge"`
+	AutoPush     bool   `config:"auto_push"`
+	Preid        string `config:"preid"`
+	TagScope     string `config:"tag_scope"`
+	TagPrefix    string `config:"tag_prefix"`
+	// TagFormat places the version between a prefix and a suffix, such as
+	// "release-{version}-final". It takes precedence over TagPrefix.
+	TagFormat string `config:"tag_format"`
+	// Path selects a module in a monorepo by its directory relative to the
+	// repository root. Its tags carry the path as a prefix, such as
+	// services/api/v1.2.3, and only commits touching it are analyzed.
+	Path       string `config:"path"`
+	Remote     string `config:"remote"`
+	SkipChecks bool   `config:"skip_checks"`
+	// QuickChecks runs the pre-release checks in quick mode too, which
+	// otherwise only runs them in interactive mode.
+	QuickChecks bool `config:"quick_checks"`
+
+	// Scheme is the versioning scheme: semver or calver.
+	Scheme string `config:"scheme"`
+	// CalVerFormat is the format of calendar versions, such as
+	// YYYY.0M.MICRO.
+	CalVerFormat string `config:"calver_format"`
+
+	// AllowFailingChecks lists checks whose failure is reported but does
+	// not block the release.
+	AllowFailingChecks []string `config:"allow_failing_checks"`
+	// BuiltinChecks enables the built-in checks of detected project types.
+	// Declared checks with the same name as a built-in check replace it.
+	BuiltinChecks bool          `config:"builtin_checks"`
+	Checks        []CheckConfig `config:"checks"`
+	// CheckWorkers is the number of checks that may run concurrently.
+	CheckWorkers int `config:"check_workers"`
+	// CheckTimeout limits each check that does not declare its own timeout.
+	CheckTimeout time.Duration `config:"check_timeout"`
+
+	// Timeout limits the whole run, including git operations.
+	Timeout time.Duration `config:"timeout"`
+
+	// APICheck compares the exported Go API with the previous tag and
+	// refuses bumps that are too small for incompatible changes.
+	APICheck bool `config:"api_check"`
+	// GoModulePath decides what happens when a new major version of a Go
+	// module needs a different module path: block, rewrite or ignore.
+	GoModulePath string `config:"go_module_path"`
+
+	Changelog       bool   `config:"changelog"`
+	ChangelogFile   string `config:"changelog_file"`
+	CommitChangelog bool   `config:"commit_changelog"`
+
+	// VersionFiles are updated with the new version in the release commit.
+	VersionFiles []VersionFileConfig `config:"version_files"`
+	// ReleaseCommitMessage is the template of the message of the release
+	// commit, which holds the files a release changes and is tagged.
+	ReleaseCommitMessage string `config:"release_commit_message"`
+	// SignCommits signs the commits bump creates with git commit -S.
+	SignCommits bool `config:"sign_commits"`
+
+	TagMessageTemplate     string `config:"tag_message_template"`
+	TagMessageTemplateFile string `config:"tag_message_template_file"`
 }
 
 func New() *Config {
@@ -21,5 +159,181 @@ func New() *Config {
 		BranchName:   "",
 		AutoMerge:    false,
 		AutoPush:     false,
+		Preid:        "",
+		TagScope:     TagScopeReachable,
+		TagPrefix:    "v",
+		TagFormat:    "",
+		Path:         "",
+		Remote:       "origin",
+		SkipChecks:   false,
+		QuickChecks:  false,
+
+		Scheme:       SchemeSemVer,
+		CalVerFormat: "YYYY.0M.MICRO",
+
+		AllowFailingChecks: nil,
+		BuiltinChecks:      true,
+		Checks:             nil,
+		CheckWorkers:       1,
+		CheckTimeout:       0,
+
+		Timeout: 0,
+
+		APICheck:     true,
+		GoModulePath: ModulePathBlock,
+
+		Changelog:       false,
+		ChangelogFile:   "CHANGELOG.md",
+		CommitChangelog: false,
+
+		VersionFiles:         nil,
+		ReleaseCommitMessage: "chore(release): {{.NewVersion}}",
+		SignCommits:          false,
+
+		TagMessageTemplate:     "",
+		TagMessageTemplateFile: "",
+	}
+}
+
+// Validate checks option values that cannot be expressed by flag types alone.
+func (c *Config) Validate() error {
+	switch c.TagScope {
+	case TagScopeReachable, TagScopeAll:
+	default:
+		return fmt.Errorf("invalid tag scope: %s (must be %s or %s)", c.TagScope, TagScopeReachable, TagScopeAll)
+	}
+
+	switch c.Scheme {
+	case SchemeSemVer:
+	case SchemeCalVer:
+		if _, err := version.NewCalVer(c.CalVerFormat); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid scheme: %s (must be %s or %s)", c.Scheme, SchemeSemVer, SchemeCalVer)
+	}
+
+	if c.TagFormat != "" {
+		if _, err := version.ParseTagFormat(c.TagFormat); err != nil {
+			return err
+		}
+	}
+
+	if c.Path != "" {
+		clean := path.Clean(filepath.ToSlash(c.Path))
+		if path.IsAbs(clean) || filepath.IsAbs(c.Path) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("invalid path: %s (must be relative to the repository root)", c.Path)
+		}
+	}
+
+	if c.Remote == "" || strings.HasPrefix(c.Remote, "-") {
+		return fmt.Errorf("invalid remote: %q", c.Remote)
+	}
+
+	switch c.GoModulePath {
+	case ModulePathBlock, ModulePathRewrite, ModulePathIgnore:
+	default:
+		return fmt.Errorf("invalid go_module_path: %s (must be %s, %s or %s)", c.GoModulePath, ModulePathBlock, ModulePathRewrite, ModulePathIgnore)
+	}
+
+	if c.CheckWorkers < 1 {
+		return fmt.Errorf("invalid number of check workers: %d (must be at least 1)", c.CheckWorkers)
+	}
+
+	if c.CheckTimeout < 0 || c.Timeout < 0 {
+		return fmt.Errorf("timeouts must not be negative")
+	}
+
+	for _, file := range c.VersionFiles {
+		if file.Path == "" {
+			return fmt.Errorf("version file without a path")
+		}
+		if err := file.File(file.Path).Validate(); err != nil {
+			return fmt.Errorf("invalid version file %s: %w", file.Path, err)
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, check := range c.Checks {
+		if check.Name == "" {
+			return fmt.Errorf("check without a name")
+		}
+		if names[check.Name] {
+			return fmt.Errorf("duplicate check: %s", check.Name)
+		}
+		names[check.Name] = true
+
+		if strings.TrimSpace(check.Run) == "" {
+			return fmt.Errorf("check %s has no command to run", check.Name)
+		}
+		switch check.Severity {
+		case "", SeverityRequired, SeverityAdvisory:
+		default:
+			return fmt.Errorf("invalid severity for check %s: %s (must be %s or %s)", check.Name, check.Severity, SeverityRequired, SeverityAdvisory)
+		}
+		if _, err := check.TimeoutDuration(); err != nil {
+			return fmt.Errorf("invalid timeout for check %s: %w", check.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ModuleDir returns Path cleaned and with forward slashes, or an empty
+// string when no module is selected.
+func (c *Config) ModuleDir() string {
+	if c.Path == "" {
+		return ""
+	}
+	dir := path.Clean(filepath.ToSlash(c.Path))
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// VersionTagFormat returns the format of version tags of the selected
+// module, or of the repository when no module is selected.
+func (c *Config) VersionTagFormat() version.TagFormat {
+	return c.TagFormatFor(c.ModuleDir())
+}
+
+// TagFormatFor returns the format of version tags of the module in dir: the
+// tag format, or the tag prefix followed by the version, preceded by dir
+// unless it is empty. An invalid tag format is rejected by Validate.
+func (c *Config) TagFormatFor(dir string) version.TagFormat {
+	format := version.TagFormat{Prefix: c.TagPrefix}
+	if c.TagFormat != "" {
+		format, _ = version.ParseTagFormat(c.TagFormat)
+	}
+	format.Scheme = c.VersionScheme()
+	if dir != "" {
+		format.Prefix = dir + "/" + format.Prefix
+	}
+	return format
+}
+
+// VersionScheme returns the configured versioning scheme. An invalid CalVer
+// format is rejected by Validate.
+func (c *Config) VersionScheme() version.Scheme {
+	if c.Scheme == SchemeCalVer {
+		if calver, err := version.NewCalVer(c.CalVerFormat); err == nil {
+			return calver
+		}
+	}
+	return version.SemVer{}
+}
+
+// File returns the version file at path, which is Path resolved by the
+// caller.
+func (f VersionFileConfig) File(path string) versionfile.File {
+	return versionfile.File{Path: path, Format: f.Format, Key: f.Key, Pattern: f.Pattern}
+}
+
+// TimeoutDuration parses Timeout, returning zero when no timeout is set.
+func (c CheckConfig) TimeoutDuration() (time.Duration, error) {
+	if c.Timeout == "" {
+		return 0, nil
 	}
+	return time.ParseDuration(c.Timeout)
 }
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// RepoFileNames are the configuration file names looked up in the
+// repository root, in order of preference.
+var RepoFileNames = []string{".bump.yaml", ".bump.yml", ".bump.toml"}
+
+// userFileNames are the configuration file names looked up in the user
+// configuration directory.
+var userFileNames = []string{"config.yaml", "config.yml", "config.toml"}
+
+const envPrefix = "BUMP_"
+
+// Loader layers configuration sources onto a Config. Sources are applied in
+// increasing order of precedence: the user file, the repository file and
+// BUMP_* environment variables. Options for which IsSet reports true, such as
+// flags given on the command line, are never overwritten.
+type Loader struct {
+	// RepoDir is the directory searched for a repository configuration file.
+	RepoDir string
+	// File, when set, replaces the repository configuration file lookup.
+	File string
+	// IsSet reports whether the option with the given key was set explicitly.
+	IsSet func(key string) bool
+
+	// Loaded lists the configuration files that were applied.
+	Loaded []string
+}
+
+// Load applies all configuration sources to cfg.
+func (l *Loader) Load(cfg *Config) error {
+	if path := userConfigFile(); path != "" {
+		if err := l.applyFile(cfg, path); err != nil {
+			return err
+		}
+	}
+
+	repoFile := l.File
+	if repoFile == "" {
+		repoFile = findFile(l.RepoDir, RepoFileNames)
+	}
+	if repoFile != "" {
+		if err := l.applyFile(cfg, repoFile); err != nil {
+			return err
+		}
+	}
+
+	return l.applyEnv(cfg)
+}
+
+// UserConfigDir returns the bump directory under $XDG_CONFIG_HOME, falling
+// back to ~/.config.
+func UserConfigDir() string {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "bump")
+}
+
+func userConfigFile() string {
+	dir := UserConfigDir()
+	if dir == "" {
+		return ""
+	}
+	return findFile(dir, userFileNames)
+}
+
+func findFile(dir string, names []string) string {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+func (l *Loader) applyFile(cfg *Config, path string) error {
+	content, err := os.ReadFile(path) // #nosec G304 -- configuration paths are chosen by the user
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	fields := configFields(cfg)
+
+	var decode func(key string, target interface{}) error
+	var keys []string
+
+	if strings.HasSuffix(path, ".toml") {
+		var values map[string]toml.Primitive
+		meta, err := toml.Decode(string(content), &values)
+		if err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		for key := range values {
+			keys = append(keys, key)
+		}
+		decode = func(key string, target interface{}) error {
+			return meta.PrimitiveDecode(values[key], target)
+		}
+	} else {
+		var values map[string]yaml.Node
+		if err := yaml.Unmarshal(content, &values); err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		for key := range values {
+			keys = append(keys, key)
+		}
+		decode = func(key string, target interface{}) error {
+			node := values[key]
+			return node.Decode(target)
+		}
+	}
+
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("unknown option %q in config file %s", key, path)
+		}
+		if l.isSet(key) {
+			continue
+		}
+		if field.Type() == durationType {
+			// Decoded by hand, as TOML would read a bare number as
+			// nanoseconds
+			var value interface{}
+			if err := decode(key, &value); err != nil {
+				return fmt.Errorf("invalid value for %q in config file %s: %w", key, path, err)
+			}
+			if err := setDuration(field, value); err != nil {
+				return fmt.Errorf("invalid value for %q in config file %s: %w", key, path, err)
+			}
+			continue
+		}
+		if err := decode(key, field.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid value for %q in config file %s: %w", key, path, err)
+		}
+	}
+
+	l.Loaded = append(l.Loaded, path)
+	return nil
+}
+
+func (l *Loader) applyEnv(cfg *Config) error {
+	for key, field := range configFields(cfg) {
+		name := envPrefix + strings.ToUpper(key)
+		value, ok := os.LookupEnv(name)
+		if !ok || l.isSet(key) {
+			continue
+		}
+		if err := setFromString(field, value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (l *Loader) isSet(key string) bool {
+	return l.IsSet != nil && l.IsSet(key)
+}
+
+// FlagName returns the command line flag name for a configuration key.
+func FlagName(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+// configFields maps configuration keys to the settable fields of cfg.
+func configFields(cfg *Config) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	value := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if key := value.Type().Field(i).Tag.Get("config"); key != "" {
+			fields[key] = value.Field(i)
+		}
+	}
+	return fields
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setDuration sets a duration from a string with a unit, such as "5m". A
+// bare number is rejected, except for 0, rather than guessing its unit.
+func setDuration(field reflect.Value, value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return setFromString(field, v)
+	case int, int64, uint64, float64:
+		if reflect.ValueOf(v).IsZero() {
+			field.SetInt(0)
+			return nil
+		}
+	}
+	return fmt.Errorf("%v is not a duration; give a unit, such as \"300s\" or \"5m\"", value)
+}
+
+func setFromString(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return errors.New("option cannot be set from the environment")
+		}
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return errors.New("option cannot be set from the environment")
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// isolate points the user configuration directory at an empty directory.
+func isolate(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	return filepath.Join(home, "bump")
+}
+
+func TestLoadLayers(t *testing.T) {
+	userDir := isolate(t)
+	repoDir := t.TempDir()
+
+	userFile := writeFile(t, filepath.Join(userDir, "config.yaml"), `
+remote: user
+tag_prefix: user-
+check_workers: 3
+preid: alpha
+sign_commits: true
+`)
+	repoFile := writeFile(t, filepath.Join(repoDir, ".bump.yaml"), `
+remote: repo
+tag_prefix: repo-
+preid: beta
+`)
+	t.Setenv("BUMP_REMOTE", "env")
+	t.Setenv("BUMP_ALLOW_FAILING_CHECKS", "lint, test,")
+
+	cfg := New()
+	cfg.Preid = "flag"
+	loader := &Loader{RepoDir: repoDir, IsSet: func(key string) bool { return key == "preid" }}
+	if err := loader.Load(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	checks := []struct {
+		key       string
+		got, want interface{}
+	}{
+		{key: "remote (environment over files)", got: cfg.Remote, want: "env"},
+		{key: "tag_prefix (repository over user)", got: cfg.TagPrefix, want: "repo-"},
+		{key: "check_workers (user over default)", got: cfg.CheckWorkers, want: 3},
+		{key: "sign_commits (user over default)", got: cfg.SignCommits, want: true},
+		{key: "preid (flag over everything)", got: cfg.Preid, want: "flag"},
+		{key: "tag_scope (default)", got: cfg.TagScope, want: TagScopeReachable},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.key, c.got, c.want)
+		}
+	}
+	if want := []string{"lint", "test"}; !slices.Equal(cfg.AllowFailingChecks, want) {
+		t.Errorf("allow_failing_checks = %q, want %q", cfg.AllowFailingChecks, want)
+	}
+	if want := []string{userFile, repoFile}; !slices.Equal(loader.Loaded, want) {
+		t.Errorf("Loaded = %q, want %q", loader.Loaded, want)
+	}
+}
+
+func TestLoadRepoFilePreference(t *testing.T) {
+	isolate(t)
+	repoDir := t.TempDir()
+	writeFile(t, filepath.Join(repoDir, ".bump.yml"), "remote: yml\n")
+	writeFile(t, filepath.Join(repoDir, ".bump.toml"), "remote = \"toml\"\n")
+	explicit := writeFile(t, filepath.Join(t.TempDir(), "release.toml"), "remote = \"explicit\"\n")
+
+	cfg := New()
+	if err := (&Loader{RepoDir: repoDir}).Load(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Remote != "yml" {
+		t.Errorf("remote = %s, want yml from .bump.yml before .bump.toml", cfg.Remote)
+	}
+
+	cfg = New()
+	if err := (&Loader{RepoDir: repoDir, File: explicit}).Load(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Remote != "explicit" {
+		t.Errorf("remote = %s, want explicit from --config", cfg.Remote)
+	}
+}
+
+func TestLoadDurations(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    time.Duration
+		wantErr string
+	}{
+		{name: "yaml string", file: ".bump.yaml", content: "check_timeout: 5m\n", want: 5 * time.Minute},
+		{name: "yaml quoted string", file: ".bump.yaml", content: "check_timeout: \"90s\"\n", want: 90 * time.Second},
+		{name: "yaml zero", file: ".bump.yaml", content: "check_timeout: 0\n", want: 0},
+		{name: "yaml integer", file: ".bump.yaml", content: "check_timeout: 300\n", wantErr: `300 is not a duration`},
+		{name: "yaml without unit", file: ".bump.yaml", content: "check_timeout: \"300\"\n", wantErr: "missing unit"},
+		{name: "toml string", file: ".bump.toml", content: "check_timeout = \"5m\"\n", want: 5 * time.Minute},
+		{name: "toml zero", file: ".bump.toml", content: "check_timeout = 0\n", want: 0},
+		{name: "toml integer", file: ".bump.toml", content: "check_timeout = 300\n", wantErr: `300 is not a duration`},
+		{name: "toml float", file: ".bump.toml", content: "check_timeout = 1.5\n", wantErr: `1.5 is not a duration`},
+		{name: "toml boolean", file: ".bump.toml", content: "check_timeout = true\n", wantErr: `true is not a duration`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			repoDir := t.TempDir()
+			writeFile(t, filepath.Join(repoDir, tt.file), tt.content)
+
+			cfg := New()
+			cfg.CheckTimeout = time.Hour
+			err := (&Loader{RepoDir: repoDir}).Load(cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if cfg.CheckTimeout != tt.want {
+				t.Errorf("check_timeout = %s, want %s", cfg.CheckTimeout, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEnvDuration(t *testing.T) {
+	isolate(t)
+
+	t.Setenv("BUMP_TIMEOUT", "300")
+	if err := (&Loader{RepoDir: t.TempDir()}).Load(New()); err == nil {
+		t.Errorf("Load accepted BUMP_TIMEOUT=300 without a unit")
+	}
+
+	t.Setenv("BUMP_TIMEOUT", "15m")
+	cfg := New()
+	if err := (&Loader{RepoDir: t.TempDir()}).Load(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Timeout != 15*time.Minute {
+		t.Errorf("timeout = %s, want 15m", cfg.Timeout)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{name: "unknown option", file: ".bump.yaml", content: "remtoe: origin\n", wantErr: `unknown option "remtoe"`},
+		{name: "wrong type", file: ".bump.toml", content: "check_workers = \"many\"\n", wantErr: `invalid value for "check_workers"`},
+		{name: "invalid yaml", file: ".bump.yaml", content: "remote: [\n", wantErr: "failed to parse config file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			repoDir := t.TempDir()
+			writeFile(t, filepath.Join(repoDir, tt.file), tt.content)
+
+			err := (&Loader{RepoDir: repoDir}).Load(New())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
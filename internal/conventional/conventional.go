@@ -0,0 +1,150 @@
+package conventional
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	BumpNone  = "none"
+	BumpPatch = "patch"
+	BumpMinor = "minor"
+	BumpMajor = "major"
+)
+
+var headerPattern = regexp.MustCompile(`^(\w+)(?:\(([^()]*)\))?(!)?: (.+)$`)
+
+// Commit is a commit message parsed according to the Conventional Commits
+// specification.
+type Commit struct {
+	Hash        string
+	Type        string
+	Scope       string
+	Description string
+	Breaking    bool
+	// BreakingNote holds the text of a BREAKING CHANGE footer, if any.
+	BreakingNote string
+	Subject      string
+	Body         string
+}
+
+// Parse parses a commit subject and body. Commits whose subject does not
+// follow the Conventional Commits header format are returned with an empty
+// Type so they can still be listed.
+func Parse(hash, subject, body string) Commit {
+	commit := Commit{
+		Hash:        hash,
+		Subject:     subject,
+		Body:        body,
+		Description: subject,
+	}
+
+	matches := headerPattern.FindStringSubmatch(strings.TrimSpace(subject))
+	if matches != nil {
+		commit.Type = strings.ToLower(matches[1])
+		commit.Scope = matches[2]
+		commit.Breaking = matches[3] == "!"
+		commit.Description = matches[4]
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		for _, token := range []string{"BREAKING CHANGE:", "BREAKING-CHANGE:"} {
+			if strings.HasPrefix(line, token) {
+				commit.Breaking = true
+				commit.BreakingNote = strings.TrimSpace(strings.TrimPrefix(line, token))
+			}
+		}
+	}
+
+	return commit
+}
+
+// IsConventional reports whether the commit subject followed the
+// Conventional Commits header format.
+func (c Commit) IsConventional() bool {
+	return c.Type != ""
+}
+
+// BumpType returns the bump this single commit calls for.
+func (c Commit) BumpType() string {
+	switch {
+	case c.Breaking:
+		return BumpMajor
+	case c.Type == "feat":
+		return BumpMinor
+	case c.Type == "fix" || c.Type == "perf":
+		return BumpPatch
+	default:
+		return BumpNone
+	}
+}
+
+func (c Commit) String() string {
+	if c.Hash == "" {
+		return c.Subject
+	}
+	return fmt.Sprintf("%s %s", c.Hash, c.Subject)
+}
+
+// Analysis is the result of analyzing a set of commits.
+type Analysis struct {
+	BumpType string
+	// Reasons lists the commits that determined BumpType.
+	Reasons []Commit
+	Commits []Commit
+}
+
+// Analyze determines the bump type called for by a set of commits: major
+// for breaking changes, minor for features, patch for fixes and performance
+// improvements, and none otherwise.
+func Analyze(commits []Commit) *Analysis {
+	analysis := &Analysis{
+		BumpType: BumpNone,
+		Commits:  commits,
+	}
+
+	for _, commit := range commits {
+		bumpType := commit.BumpType()
+		if bumpType == BumpNone {
+			continue
+		}
+
+		switch {
+		case rank(bumpType) > rank(analysis.BumpType):
+			analysis.BumpType = bumpType
+			analysis.Reasons = []Commit{commit}
+		case rank(bumpType) == rank(analysis.BumpType):
+			analysis.Reasons = append(analysis.Reasons, commit)
+		}
+	}
+
+	return analysis
+}
+
+// Summary describes why the bump type was chosen.
+func (a *Analysis) Summary() string {
+	switch a.BumpType {
+	case BumpMajor:
+		return fmt.Sprintf("%d breaking change(s)", len(a.Reasons))
+	case BumpMinor:
+		return fmt.Sprintf("%d new feature(s)", len(a.Reasons))
+	case BumpPatch:
+		return fmt.Sprintf("%d fix(es)", len(a.Reasons))
+	default:
+		return fmt.Sprintf("no releasable changes in %d commit(s)", len(a.Commits))
+	}
+}
+
+func rank(bumpType string) int {
+	switch bumpType {
+	case BumpMajor:
+		return 3
+	case BumpMinor:
+		return 2
+	case BumpPatch:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,124 @@
+package conventional
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		subject      string
+		body         string
+		typ          string
+		scope        string
+		description  string
+		breaking     bool
+		breakingNote string
+	}{
+		{name: "type", subject: "fix: handle empty tags", typ: "fix", description: "handle empty tags"},
+		{name: "scope", subject: "feat(api): add Push", typ: "feat", scope: "api", description: "add Push"},
+		{name: "upper case type", subject: "Feat: add Push", typ: "feat", description: "add Push"},
+		{name: "surrounding spaces", subject: "  fix: trim  ", typ: "fix", description: "trim"},
+		{name: "bang", subject: "feat!: drop Go 1.20", typ: "feat", description: "drop Go 1.20", breaking: true},
+		{name: "bang with scope", subject: "refactor(config)!: rename keys", typ: "refactor", scope: "config", description: "rename keys", breaking: true},
+		{
+			name:         "breaking change footer",
+			subject:      "feat: new config format",
+			body:         "Details.\n\nBREAKING CHANGE: the YAML keys changed\nRefs: #12",
+			typ:          "feat",
+			description:  "new config format",
+			breaking:     true,
+			breakingNote: "the YAML keys changed",
+		},
+		{
+			name:         "breaking change footer with a dash",
+			subject:      "fix: stricter parsing",
+			body:         "BREAKING-CHANGE: invalid tags are rejected",
+			typ:          "fix",
+			description:  "stricter parsing",
+			breaking:     true,
+			breakingNote: "invalid tags are rejected",
+		},
+		{name: "breaking change in the text", subject: "docs: explain", body: "This is not a BREAKING CHANGE: it is indented", typ: "docs", description: "explain"},
+		{name: "lower case footer", subject: "fix: x", body: "breaking change: no", typ: "fix", description: "x"},
+		{name: "not conventional", subject: "Update README", description: "Update README"},
+		{name: "missing space", subject: "fix:no space", description: "fix:no space"},
+		{name: "nested parentheses", subject: "fix(a(b)): x", description: "fix(a(b)): x"},
+		{name: "merge commit", subject: "Merge branch 'main' into dev", description: "Merge branch 'main' into dev"},
+		{name: "footer on a non-conventional commit", subject: "Rework", body: "BREAKING CHANGE: everything", description: "Rework", breaking: true, breakingNote: "everything"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Parse("abc1234", tt.subject, tt.body)
+			if c.Type != tt.typ || c.Scope != tt.scope || c.Description != tt.description {
+				t.Errorf("Parse(%q) = type %q, scope %q, description %q; want %q, %q, %q", tt.subject, c.Type, c.Scope, c.Description, tt.typ, tt.scope, tt.description)
+			}
+			if c.Breaking != tt.breaking || c.BreakingNote != tt.breakingNote {
+				t.Errorf("Parse(%q) = breaking %v, note %q; want %v, %q", tt.subject, c.Breaking, c.BreakingNote, tt.breaking, tt.breakingNote)
+			}
+			if c.IsConventional() != (tt.typ != "") {
+				t.Errorf("IsConventional() = %v, want %v", c.IsConventional(), tt.typ != "")
+			}
+			if c.Hash != "abc1234" || c.Subject != tt.subject || c.Body != tt.body {
+				t.Errorf("Parse(%q) did not keep the hash, subject and body", tt.subject)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		subjects []string
+		bodies   map[int]string
+		want     string
+		reasons  []string
+	}{
+		{name: "no commits", want: BumpNone},
+		{name: "nothing releasable", subjects: []string{"docs: readme", "chore: deps", "Update CI"}, want: BumpNone},
+		{name: "fix", subjects: []string{"chore: deps", "fix: a"}, want: BumpPatch, reasons: []string{"fix: a"}},
+		{name: "perf", subjects: []string{"perf: faster"}, want: BumpPatch, reasons: []string{"perf: faster"}},
+		{name: "feature over fixes", subjects: []string{"fix: a", "feat: b", "fix: c", "feat(x): d"}, want: BumpMinor, reasons: []string{"feat: b", "feat(x): d"}},
+		{name: "bang over features", subjects: []string{"feat: a", "fix!: b"}, want: BumpMajor, reasons: []string{"fix!: b"}},
+		{name: "footer over features", subjects: []string{"feat: a", "chore: b"}, bodies: map[int]string{1: "BREAKING CHANGE: removed"}, want: BumpMajor, reasons: []string{"chore: b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commits []Commit
+			for i, subject := range tt.subjects {
+				commits = append(commits, Parse("", subject, tt.bodies[i]))
+			}
+
+			analysis := Analyze(commits)
+			if analysis.BumpType != tt.want {
+				t.Errorf("Analyze() = %s, want %s", analysis.BumpType, tt.want)
+			}
+			if len(analysis.Commits) != len(commits) {
+				t.Errorf("Analyze() kept %d commits, want %d", len(analysis.Commits), len(commits))
+			}
+
+			var reasons []string
+			for _, reason := range analysis.Reasons {
+				reasons = append(reasons, reason.Subject)
+			}
+			if len(reasons) != len(tt.reasons) {
+				t.Fatalf("Analyze() reasons = %q, want %q", reasons, tt.reasons)
+			}
+			for i := range reasons {
+				if reasons[i] != tt.reasons[i] {
+					t.Errorf("Analyze() reasons = %q, want %q", reasons, tt.reasons)
+				}
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	commits := []Commit{Parse("", "feat: a", ""), Parse("", "feat: b", ""), Parse("", "fix: c", "")}
+	if got, want := Analyze(commits).Summary(), "2 new feature(s)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	if got, want := Analyze(commits[:0]).Summary(), "no releasable changes in 0 commit(s)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package git
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ypeckstadt/bump/internal/command"
+)
+
+// ExportTree writes the files of ref to dir without touching the working
+// tree or the index, so an older version can be inspected during a dry run.
+func (g *Client) ExportTree(ctx context.Context, ref, dir string) error {
+	cmd := command.New(ctx, "git", "archive", "--format=tar", ref)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to export %s: %w", ref, err)
+	}
+
+	extractErr := extractTar(stdout, dir)
+	// Drain the rest so git can exit when extraction stopped early
+	_, _ = io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("failed to export %s: %w", ref, err)
+	}
+	if extractErr != nil {
+		return fmt.Errorf("failed to export %s: %w", ref, extractErr)
+	}
+
+	return nil
+}
+
+// extractTar writes the directories and regular files of the archive to
+// dir. Other entries, such as symlinks, are skipped.
+func extractTar(r io.Reader, dir string) error {
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(header.Name)) // #nosec G305 -- checked below
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o750); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(path, archive, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm) // #nosec G304 -- path is inside the export directory
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil { // #nosec G110 -- archive of the local repository
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
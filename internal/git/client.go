@@ -1,9 +1,13 @@
 package git
 
 import (
+	"context"
+	"github.com/ypeckstadt/bump/internal/command"
 	"github.com/ypeckstadt/bump/internal/config"
+	"github.com/ypeckstadt/bump/internal/version"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
@@ -18,14 +22,38 @@ func NewClient(cfg *config.Config) *Client {
 	}
 }
 
-func (g *Client) IsGitRepo() bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
+func (g *Client) IsGitRepo(ctx context.Context) bool {
+	cmd := command.New(ctx, "git", "rev-parse", "--git-dir")
 	err := cmd.Run()
 	return err == nil
 }
 
-func (g *Client) IsWorkingDirectoryClean() (bool, error) {
-	cmd := exec.Command("git", "status", "--porcelain")
+// GetRepoRoot returns the top-level directory of the working tree.
+func (g *Client) GetRepoRoot(ctx context.Context) (string, error) {
+	cmd := command.New(ctx, "git", "rev-parse", "--show-toplevel")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GitPath returns the absolute path of path inside the git directory, such
+// as .git/bump, also in worktrees.
+func (g *Client) GitPath(ctx context.Context, path string) (string, error) {
+	cmd := command.New(ctx, "git", "rev-parse", "--git-path", path)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %s in the git directory: %w", path, err)
+	}
+
+	// The path is relative to the current directory
+	return filepath.Abs(strings.TrimSpace(string(output)))
+}
+
+func (g *Client) IsWorkingDirectoryClean(ctx context.Context) (bool, error) {
+	cmd := command.New(ctx, "git", "status", "--porcelain")
 	output, err := cmd.Output()
 	if err != nil {
 		return false, fmt.Errorf("failed to check git status: %w", err)
@@ -34,20 +62,77 @@ func (g *Client) IsWorkingDirectoryClean() (bool, error) {
 	return len(strings.TrimSpace(string(output))) == 0, nil
 }
 
-func (g *Client) GetLatestTag() (string, error) {
-	cmd := exec.Command("git", "describe", "--tags", "--abbrev=0")
+// HasLocalChanges reports whether path has changes that are not committed,
+// staged or not, or is not tracked at all.
+func (g *Client) HasLocalChanges(ctx context.Context, path string) (bool, error) {
+	cmd := command.New(ctx, "git", "status", "--porcelain", "--", path) // #nosec G204
 	output, err := cmd.Output()
 	if err != nil {
+		return false, fmt.Errorf("failed to check git status of %s: %w", path, err)
+	}
+
+	return len(strings.TrimSpace(string(output))) > 0, nil
+}
+
+// GetLatestTag returns the tag with the highest semantic version. Tags that
+// do not match the version tag format are ignored, so a selected module only
+// sees its own tags. Unless the tag scope is "all", only tags reachable from
+// HEAD are considered.
+func (g *Client) GetLatestTag(ctx context.Context) (string, error) {
+	tags, err := g.ListTags(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var versions []*version.Version
+	for _, tag := range tags {
+		if v, err := g.cfg.VersionTagFormat().Parse(tag); err == nil {
+			versions = append(versions, v)
+		}
+	}
+
+	latest := version.Latest(versions)
+	if latest == nil {
 		return "", fmt.Errorf("no tags found")
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	return latest.Raw, nil
 }
 
-func (g *Client) GetCommitsSinceTag(tag string) ([]string, error) {
+// ListTags returns the names of the tags in the tag scope.
+func (g *Client) ListTags(ctx context.Context) ([]string, error) {
+	args := []string{"tag", "--list"}
+	switch g.cfg.TagScope {
+	case "", config.TagScopeReachable:
+		args = append(args, "--merged", "HEAD")
+	case config.TagScopeAll:
+	default:
+		return nil, fmt.Errorf("invalid tag scope: %s", g.cfg.TagScope)
+	}
+
+	cmd := command.New(ctx, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return strings.Fields(string(output)), nil
+}
+
+// pathspec limits git log to the selected module, if any. The path is
+// relative to the repository root wherever bump runs.
+func (g *Client) pathspec() []string {
+	dir := g.cfg.ModuleDir()
+	if dir == "" {
+		return nil
+	}
+	return []string{"--", ":(top)" + dir}
+}
+
+func (g *Client) GetCommitsSinceTag(ctx context.Context, tag string) ([]string, error) {
 	var cmd *exec.Cmd
-	if tag == "" || tag == "v0.0.0" {
-		cmd = exec.Command("git", "log", "--oneline", "-10")
+	if tag == "" {
+		cmd = command.New(ctx, "git", append([]string{"log", "--oneline", "-10"}, g.pathspec()...)...) // #nosec G204
 	} else {
 		// Validate tag format to prevent command injection
 		if !isValidGitTag(tag) {
@@ -56,7 +141,7 @@ func (g *Client) GetCommitsSinceTag(tag string) ([]string, error) {
 		// Use git log with explicit revision range
 		// Input is validated by isValidGitTag() to prevent command injection
 		revRange := tag + "..HEAD"
-		cmd = exec.Command("git", "log", "--oneline", revRange) // #nosec G204
+		cmd = command.New(ctx, "git", append([]string{"log", "--oneline", revRange}, g.pathspec()...)...) // #nosec G204
 	}
 
 	output, err := cmd.Output()
@@ -72,13 +157,55 @@ func (g *Client) GetCommitsSinceTag(tag string) ([]string, error) {
 	return lines, nil
 }
 
-func (g *Client) CreateTag(tag, message string) error {
+// Commit holds the parts of a commit message needed for analysis.
+type Commit struct {
+	Hash    string
+	Subject string
+	Body    string
+}
+
+// GetCommitMessagesSinceTag returns the full messages of all commits after
+// tag, or of the entire history when tag is empty.
+func (g *Client) GetCommitMessagesSinceTag(ctx context.Context, tag string) ([]Commit, error) {
+	args := []string{"log", "--format=%h%x1f%s%x1f%b%x1e"}
+	if tag != "" {
+		// Validate tag format to prevent command injection
+		if !isValidGitTag(tag) {
+			return nil, fmt.Errorf("invalid git tag format: %s", tag)
+		}
+		args = append(args, tag+"..HEAD")
+	}
+	args = append(args, g.pathspec()...)
+
+	cmd := command.New(ctx, "git", args...) // #nosec G204
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Subject: fields[1],
+			Body:    strings.TrimSpace(fields[2]),
+		})
+	}
+
+	return commits, nil
+}
+
+func (g *Client) CreateTag(ctx context.Context, tag, message string) error {
 	if g.cfg.DryRun {
 		fmt.Printf("[DRY RUN] Would create tag: %s with message: %s\n", tag, message)
 		return nil
 	}
 
-	cmd := exec.Command("git", "tag", "-a", tag, "-m", message)
+	cmd := command.New(ctx, "git", "tag", "-a", tag, "-m", message)
 	err := cmd.Run()
 	if err != nil {
 		return fmt.Errorf("failed to create tag %s: %w", tag, err)
@@ -87,23 +214,50 @@ func (g *Client) CreateTag(tag, message string) error {
 	return nil
 }
 
-func (g *Client) PushTag(tag string) error {
+// DeleteTag removes a local tag.
+func (g *Client) DeleteTag(ctx context.Context, tag string) error {
 	if g.cfg.DryRun {
-		fmt.Printf("[DRY RUN] Would push tag: %s\n", tag)
+		fmt.Printf("[DRY RUN] Would delete tag: %s\n", tag)
 		return nil
 	}
 
-	cmd := exec.Command("git", "push", "origin", tag)
-	err := cmd.Run()
-	if err != nil {
-		return fmt.Errorf("failed to push tag %s: %w", tag, err)
+	cmd := command.New(ctx, "git", "tag", "-d", tag)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete tag %s: %s", tag, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// CommitFiles stages the given paths and commits only those paths, leaving
+// any other changes in the working tree untouched.
+func (g *Client) CommitFiles(ctx context.Context, message string, paths ...string) error {
+	if g.cfg.DryRun {
+		fmt.Printf("[DRY RUN] Would commit %s with message: %s\n", strings.Join(paths, ", "), message)
+		return nil
+	}
+
+	addArgs := append([]string{"add", "--"}, paths...)
+	cmd := command.New(ctx, "git", addArgs...) // #nosec G204
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage %s: %s", strings.Join(paths, ", "), strings.TrimSpace(string(output)))
+	}
+
+	commitArgs := []string{"commit", "-m", message}
+	if g.cfg.SignCommits {
+		commitArgs = append(commitArgs, "-S")
+	}
+	commitArgs = append(append(commitArgs, "--"), paths...)
+	cmd = command.New(ctx, "git", commitArgs...) // #nosec G204
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to commit %s: %s", strings.Join(paths, ", "), strings.TrimSpace(string(output)))
 	}
 
 	return nil
 }
 
-func (g *Client) TagExists(tag string) bool {
-	cmd := exec.Command("git", "tag", "-l", tag)
+func (g *Client) TagExists(ctx context.Context, tag string) bool {
+	cmd := command.New(ctx, "git", "tag", "-l", tag)
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -112,8 +266,8 @@ func (g *Client) TagExists(tag string) bool {
 	return strings.TrimSpace(string(output)) == tag
 }
 
-func (g *Client) GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "branch", "--show-current")
+func (g *Client) GetCurrentBranch(ctx context.Context) (string, error) {
+	cmd := command.New(ctx, "git", "branch", "--show-current")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
@@ -122,13 +276,29 @@ func (g *Client) GetCurrentBranch() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-func (g *Client) CheckoutBranch(branch string) error {
+// GetUserName returns the name git will record as committer and tagger,
+// honouring both git config and the GIT_COMMITTER_* environment variables.
+func (g *Client) GetUserName(ctx context.Context) (string, error) {
+	cmd := command.New(ctx, "git", "var", "GIT_COMMITTER_IDENT")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get git user name: %w", err)
+	}
+
+	ident := strings.TrimSpace(string(output))
+	if i := strings.Index(ident, " <"); i >= 0 {
+		ident = ident[:i]
+	}
+	return ident, nil
+}
+
+func (g *Client) CheckoutBranch(ctx context.Context, branch string) error {
 	if g.cfg.DryRun {
 		fmt.Printf("[DRY RUN] Would checkout branch: %s\n", branch)
 		return nil
 	}
 
-	cmd := exec.Command("git", "checkout", branch)
+	cmd := command.New(ctx, "git", "checkout", branch)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to checkout branch %s: %w", branch, err)
 	}
@@ -136,21 +306,21 @@ func (g *Client) CheckoutBranch(branch string) error {
 	return nil
 }
 
-func (g *Client) GetDefaultBranch() (string, error) {
-	// Try to get the default branch from remote origin
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "origin/HEAD")
+func (g *Client) GetDefaultBranch(ctx context.Context) (string, error) {
+	// Try to get the default branch from the remote
+	cmd := command.New(ctx, "git", "rev-parse", "--abbrev-ref", g.cfg.Remote+"/HEAD")
 	output, err := cmd.Output()
 	if err == nil {
-		parts := strings.Split(strings.TrimSpace(string(output)), "/")
-		if len(parts) > 1 {
-			return parts[1], nil
+		branch := strings.TrimPrefix(strings.TrimSpace(string(output)), g.cfg.Remote+"/")
+		if branch != "" && branch != g.cfg.Remote+"/HEAD" {
+			return branch, nil
 		}
 	}
 
 	// Fallback: check for common default branch names
 	branches := []string{"main", "master"}
 	for _, branch := range branches {
-		if g.BranchExists(branch) {
+		if g.BranchExists(ctx, branch) {
 			return branch, nil
 		}
 	}
@@ -158,26 +328,26 @@ func (g *Client) GetDefaultBranch() (string, error) {
 	return "main", nil
 }
 
-func (g *Client) BranchExists(branch string) bool {
-	cmd := exec.Command("git", "rev-parse", "--verify", branch)
+func (g *Client) BranchExists(ctx context.Context, branch string) bool {
+	cmd := command.New(ctx, "git", "rev-parse", "--verify", branch)
 	err := cmd.Run()
 	return err == nil
 }
 
-func (g *Client) CreateBranch(branch, sourceBranch string) error {
+func (g *Client) CreateBranch(ctx context.Context, branch, sourceBranch string) error {
 	if g.cfg.DryRun {
 		fmt.Printf("[DRY RUN] Would create branch: %s from %s\n", branch, sourceBranch)
 		return nil
 	}
 
 	// Checkout source branch first
-	cmd := exec.Command("git", "checkout", sourceBranch)
+	cmd := command.New(ctx, "git", "checkout", sourceBranch)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to checkout source branch %s: %w", sourceBranch, err)
 	}
 
 	// Create and checkout new branch
-	cmd = exec.Command("git", "checkout", "-b", branch)
+	cmd = command.New(ctx, "git", "checkout", "-b", branch)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to create branch %s: %w", branch, err)
 	}
@@ -185,44 +355,83 @@ func (g *Client) CreateBranch(branch, sourceBranch string) error {
 	return nil
 }
 
-func (g *Client) MergeBranch(sourceBranch, targetBranch string) error {
+func (g *Client) MergeBranch(ctx context.Context, sourceBranch, targetBranch string) error {
 	if g.cfg.DryRun {
 		fmt.Printf("[DRY RUN] Would merge branch: %s into %s\n", sourceBranch, targetBranch)
 		return nil
 	}
 
 	// Checkout target branch
-	cmd := exec.Command("git", "checkout", targetBranch)
+	cmd := command.New(ctx, "git", "checkout", targetBranch)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to checkout target branch %s: %w", targetBranch, err)
 	}
 
 	// Merge source branch
-	cmd = exec.Command("git", "merge", sourceBranch)
+	cmd = command.New(ctx, "git", "merge", sourceBranch)
 	if err := cmd.Run(); err != nil {
+		// Don't leave a conflicted merge behind
+		_ = command.New(context.WithoutCancel(ctx), "git", "merge", "--abort").Run()
 		return fmt.Errorf("failed to merge branch %s into %s: %w", sourceBranch, targetBranch, err)
 	}
 
 	return nil
 }
 
-func (g *Client) PushBranch(branch string) error {
+// DeleteBranch force deletes a local branch.
+func (g *Client) DeleteBranch(ctx context.Context, branch string) error {
 	if g.cfg.DryRun {
-		fmt.Printf("[DRY RUN] Would push branch: %s\n", branch)
+		fmt.Printf("[DRY RUN] Would delete branch: %s\n", branch)
 		return nil
 	}
 
-	cmd := exec.Command("git", "push", "origin", branch)
-	err := cmd.Run()
+	cmd := command.New(ctx, "git", "branch", "-D", branch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %s", branch, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// ResolveRef returns the commit ref points to.
+func (g *Client) ResolveRef(ctx context.Context, ref string) (string, error) {
+	cmd := command.New(ctx, "git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ResetBranch points branch at commit. The current branch, or HEAD when
+// branch is empty, is reset with --keep so that other local changes stay
+// in the working tree.
+func (g *Client) ResetBranch(ctx context.Context, branch, commit string) error {
+	if g.cfg.DryRun {
+		fmt.Printf("[DRY RUN] Would reset %s to %s\n", branch, commit)
+		return nil
+	}
+
+	current, err := g.GetCurrentBranch(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to push branch %s: %w", branch, err)
+		return err
+	}
+
+	args := []string{"branch", "--force", branch, commit}
+	if branch == current {
+		args = []string{"reset", "--keep", commit}
+	}
+	cmd := command.New(ctx, "git", args...) // #nosec G204
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset %s to %s: %s", branch, commit, strings.TrimSpace(string(output)))
 	}
 
 	return nil
 }
 
-func (g *Client) GetAllTags() ([]string, error) {
-	cmd := exec.Command("git", "for-each-ref", "--sort=-creatordate", "--format=%(refname:short) %(creatordate:iso)", "refs/tags")
+func (g *Client) GetAllTags(ctx context.Context) ([]string, error) {
+	cmd := command.New(ctx, "git", "for-each-ref", "--sort=-creatordate", "--format=%(refname:short) %(creatordate:iso)", "refs/tags")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tags: %w", err)
@@ -239,9 +448,10 @@ func (g *Client) GetAllTags() ([]string, error) {
 // isValidGitTag validates that a git tag contains only safe characters
 // to prevent command injection attacks
 func isValidGitTag(tag string) bool {
-	// Git tags should only contain alphanumeric, dots, hyphens, underscores, and forward slashes
-	// This regex allows semantic versioning tags like v1.2.3, v1.2.3-alpha, etc.
-	validTagPattern := regexp.MustCompile(`^[a-zA-Z0-9._/-]+$`)
+	// Git tags should only contain alphanumeric, dots, hyphens, underscores, forward slashes,
+	// plus signs and at signs
+	// This regex allows semantic versioning tags like v1.2.3, v1.2.3-alpha+build.1, api@1.2.3, etc.
+	validTagPattern := regexp.MustCompile(`^[a-zA-Z0-9._/+@-]+$`)
 
 	// Additional length check to prevent excessively long inputs
 	if len(tag) > 100 {
@@ -0,0 +1,47 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/ypeckstadt/bump/internal/command"
+)
+
+// Diff returns the diff git shows for changing the file at the slash path
+// name from old to new, without touching the file. A nil before means the file
+// is created.
+func (g *Client) Diff(ctx context.Context, name string, before, after []byte) (string, error) {
+	tmp, err := os.MkdirTemp("", "bump-diff-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	oldPath := "/dev/null"
+	if before != nil {
+		oldPath = "a/" + name
+		if err := writeFile(filepath.Join(tmp, "a", filepath.FromSlash(name)), bytes.NewReader(before), 0o600); err != nil {
+			return "", err
+		}
+	}
+	newPath := "b/" + name
+	if err := writeFile(filepath.Join(tmp, "b", filepath.FromSlash(name)), bytes.NewReader(after), 0o600); err != nil {
+		return "", err
+	}
+
+	cmd := command.New(ctx, "git", "diff", "--no-index", "--no-prefix", "--no-color", "--", oldPath, newPath)
+	cmd.Dir = tmp
+	output, err := cmd.Output()
+	// git diff exits with 1 when the files differ
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		return "", fmt.Errorf("failed to diff %s: %w", name, err)
+	}
+
+	return string(output), nil
+}
@@ -0,0 +1,115 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/ypeckstadt/bump/internal/command"
+)
+
+// RejectedRef is a ref the remote did not accept, with the reason git
+// reports, such as "non-fast-forward" or "already exists".
+type RejectedRef struct {
+	Ref    string
+	Reason string
+}
+
+// PushError is returned when an atomic push is rejected. The remote was
+// left unchanged, so none of the refs were pushed.
+type PushError struct {
+	// Rejected lists the refs that caused the push to fail. Refs that were
+	// only held back because the push is atomic are not included.
+	Rejected []RejectedRef
+	Output   string
+}
+
+func (e *PushError) Error() string {
+	if len(e.Rejected) == 0 {
+		return fmt.Sprintf("push rejected, nothing was pushed: %s", e.Output)
+	}
+
+	rejected := make([]string, 0, len(e.Rejected))
+	for _, ref := range e.Rejected {
+		rejected = append(rejected, fmt.Sprintf("%s (%s)", ShortRef(ref.Ref), ref.Reason))
+	}
+	return fmt.Sprintf("push rejected for %s, nothing was pushed", strings.Join(rejected, ", "))
+}
+
+// Push pushes refs, such as refs/heads/main and refs/tags/v1.2.3, to the
+// same refs on the remote in a single atomic push, so the remote either
+// accepts all of them or none. A rejection is returned as a *PushError.
+func (g *Client) Push(ctx context.Context, refs ...string) error {
+	refspecs := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		refspecs = append(refspecs, ref+":"+ref)
+	}
+
+	if g.cfg.DryRun {
+		fmt.Printf("[DRY RUN] Would push atomically to %s: %s\n", g.cfg.Remote, strings.Join(refspecs, " "))
+		return nil
+	}
+
+	args := append([]string{"push", "--atomic", "--porcelain", g.cfg.Remote}, refspecs...)
+	cmd := command.New(ctx, "git", args...) // #nosec G204
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *exec.ExitError
+	if ctx.Err() != nil || !errors.As(err, &exitErr) {
+		return fmt.Errorf("failed to push %s: %w", strings.Join(refs, ", "), err)
+	}
+
+	rejected, found := parsePorcelain(string(stdout))
+	if !found {
+		return fmt.Errorf("failed to push %s: %s", strings.Join(refs, ", "), strings.TrimSpace(stderr.String()))
+	}
+	return &PushError{Rejected: rejected, Output: strings.TrimSpace(stderr.String())}
+}
+
+// parsePorcelain returns the refs git push --porcelain reports as rejected
+// for a reason of their own. found is false when no ref was rejected, so the
+// push failed before the remote decided on the refs.
+func parsePorcelain(output string) (rejected []RejectedRef, found bool) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		// Lines look like "!\trefs/heads/main:refs/heads/main\t[rejected] (non-fast-forward)"
+		fields := strings.SplitN(scanner.Text(), "\t", 3)
+		if len(fields) != 3 || fields[0] != "!" {
+			continue
+		}
+		found = true
+
+		_, ref, _ := strings.Cut(fields[1], ":")
+		reason := fields[2]
+		if open := strings.Index(reason, "("); open >= 0 && strings.HasSuffix(reason, ")") {
+			reason = reason[open+1 : len(reason)-1]
+		}
+		if reason == "atomic push failed" {
+			continue
+		}
+		rejected = append(rejected, RejectedRef{Ref: ref, Reason: reason})
+	}
+
+	return rejected, found
+}
+
+// ShortRef returns a ref as people name it: "branch main" for
+// refs/heads/main and "tag v1.2.3" for refs/tags/v1.2.3.
+func ShortRef(ref string) string {
+	switch {
+	case strings.HasPrefix(ref, "refs/heads/"):
+		return "branch " + strings.TrimPrefix(ref, "refs/heads/")
+	case strings.HasPrefix(ref, "refs/tags/"):
+		return "tag " + strings.TrimPrefix(ref, "refs/tags/")
+	default:
+		return ref
+	}
+}
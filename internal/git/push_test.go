@@ -0,0 +1,84 @@
+package git
+
+import (
+	"slices"
+	"testing"
+)
+
+// The outputs below were recorded from git push --atomic --porcelain
+// against a local bare repository.
+func TestParsePorcelain(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		rejected []RejectedRef
+		found    bool
+	}{
+		{
+			name: "all pushed",
+			output: "To ../remote.git\n" +
+				"=\trefs/tags/v1.0.0:refs/tags/v1.0.0\t[up to date]\n" +
+				" \trefs/heads/main:refs/heads/main\t23af59c..fd92806\n" +
+				"*\trefs/tags/v1.0.1:refs/tags/v1.0.1\t[new tag]\n" +
+				"Done\n",
+			found: false,
+		},
+		{
+			name: "one rejected",
+			output: "To ../remote.git\n" +
+				"!\trefs/heads/main:refs/heads/main\t[rejected] (fetch first)\n" +
+				"!\trefs/tags/v1.0.4:refs/tags/v1.0.4\t[rejected] (atomic push failed)\n" +
+				"Done\n",
+			rejected: []RejectedRef{{Ref: "refs/heads/main", Reason: "fetch first"}},
+			found:    true,
+		},
+		{
+			name: "tag already exists",
+			output: "To ../remote.git\n" +
+				"!\trefs/tags/v1.0.0:refs/tags/v1.0.0\t[rejected] (already exists)\n" +
+				"!\trefs/tags/v1.0.4:refs/tags/v1.0.4\t[rejected] (atomic push failed)\n" +
+				"Done\n",
+			rejected: []RejectedRef{{Ref: "refs/tags/v1.0.0", Reason: "already exists"}},
+			found:    true,
+		},
+		{
+			name: "declined by the remote",
+			output: "To ../remote.git\n" +
+				"=\trefs/tags/v1.0.2:refs/tags/v1.0.2\t[up to date]\n" +
+				"!\trefs/tags/v1.0.3:refs/tags/v1.0.3\t[remote rejected] (pre-receive hook declined)\n" +
+				"Done\n",
+			rejected: []RejectedRef{{Ref: "refs/tags/v1.0.3", Reason: "pre-receive hook declined"}},
+			found:    true,
+		},
+		{
+			// The remote could not be reached, so git reports nothing on
+			// stdout and the reason on stderr
+			name:   "remote error",
+			output: "",
+			found:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejected, found := parsePorcelain(tt.output)
+			if found != tt.found {
+				t.Errorf("parsePorcelain() found = %v, want %v", found, tt.found)
+			}
+			if !slices.Equal(rejected, tt.rejected) {
+				t.Errorf("parsePorcelain() = %v, want %v", rejected, tt.rejected)
+			}
+		})
+	}
+}
+
+func TestPushErrorMessage(t *testing.T) {
+	err := &PushError{Rejected: []RejectedRef{
+		{Ref: "refs/heads/main", Reason: "fetch first"},
+		{Ref: "refs/tags/v1.0.4", Reason: "already exists"},
+	}}
+	want := "push rejected for branch main (fetch first), tag v1.0.4 (already exists), nothing was pushed"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
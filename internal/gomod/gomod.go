@@ -0,0 +1,148 @@
+// Package gomod keeps the module path of a Go module in line with its major
+// version, which Go requires from v2 on (example.com/lib/v2).
+package gomod
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// ModulePath returns the module path declared in dir/go.mod.
+func ModulePath(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod")) // #nosec G304 -- go.mod of the repository
+	if err != nil {
+		return "", err
+	}
+
+	path := modfile.ModulePath(data)
+	if path == "" {
+		return "", fmt.Errorf("no module path in %s", filepath.Join(dir, "go.mod"))
+	}
+	return path, nil
+}
+
+// MajorPath returns path with the major version suffix Go expects for
+// major: none below v2, /vN from v2 on, and .vN for gopkg.in paths.
+func MajorPath(path string, major int) (string, error) {
+	prefix, _, ok := module.SplitPathVersion(path)
+	if !ok {
+		return "", fmt.Errorf("invalid module path %s", path)
+	}
+
+	switch {
+	case strings.HasPrefix(path, "gopkg.in/"):
+		return fmt.Sprintf("%s.v%d", prefix, major), nil
+	case major < 2:
+		return prefix, nil
+	default:
+		return fmt.Sprintf("%s/v%d", prefix, major), nil
+	}
+}
+
+// Rewrite changes the module path in dir/go.mod from oldPath to newPath and
+// rewrites imports of oldPath and its packages in the module's Go files.
+// Nested modules, hidden directories, vendor and testdata are left alone.
+// It returns the new content of the changed files, keyed by their slash
+// path relative to dir, without writing anything.
+func Rewrite(dir, oldPath, newPath string) (map[string][]byte, error) {
+	changed := make(map[string][]byte)
+
+	goModPath := filepath.Join(dir, "go.mod")
+	data, err := os.ReadFile(goModPath) // #nosec G304 -- go.mod of the repository
+	if err != nil {
+		return nil, err
+	}
+	file, err := modfile.Parse(goModPath, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.AddModuleStmt(newPath); err != nil {
+		return nil, err
+	}
+	changed["go.mod"] = modfile.Format(file.Syntax)
+
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path == dir {
+				return nil
+			}
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		source, err := os.ReadFile(path) // #nosec G304 -- file inside the module
+		if err != nil {
+			return err
+		}
+		rewritten, ok, err := rewriteImports(path, source, oldPath, newPath)
+		if err != nil || !ok {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		changed[filepath.ToSlash(rel)] = rewritten
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changed, nil
+}
+
+// rewriteImports replaces imports of oldPath and its packages with newPath
+// in source, leaving everything else byte for byte. It reports whether any
+// import changed.
+func rewriteImports(filename string, source []byte, oldPath, newPath string) ([]byte, bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, source, parser.ImportsOnly)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var out []byte
+	last := 0
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, false, err
+		}
+		if path != oldPath && !strings.HasPrefix(path, oldPath+"/") {
+			continue
+		}
+
+		start := fset.Position(spec.Path.Pos()).Offset
+		end := fset.Position(spec.Path.End()).Offset
+		out = append(out, source[last:start]...)
+		out = append(out, strconv.Quote(newPath+strings.TrimPrefix(path, oldPath))...)
+		last = end
+	}
+	if out == nil {
+		return source, false, nil
+	}
+
+	return append(out, source[last:]...), true, nil
+}
@@ -0,0 +1,152 @@
+package gomod
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"golang.org/x/exp/maps"
+)
+
+func TestMajorPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		major   int
+		want    string
+		wantErr bool
+	}{
+		{path: "example.com/lib", major: 1, want: "example.com/lib"},
+		{path: "example.com/lib", major: 2, want: "example.com/lib/v2"},
+		{path: "example.com/lib/v2", major: 3, want: "example.com/lib/v3"},
+		{path: "example.com/lib/v3", major: 1, want: "example.com/lib"},
+		{path: "example.com/lib/v2", major: 0, want: "example.com/lib"},
+		{path: "gopkg.in/yaml.v2", major: 3, want: "gopkg.in/yaml.v3"},
+		{path: "example.com/lib/v1", major: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := MajorPath(tt.path, tt.major)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("MajorPath(%q, %d) = %s, want an error", tt.path, tt.major, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MajorPath(%q, %d) failed: %v", tt.path, tt.major, err)
+			}
+			if got != tt.want {
+				t.Errorf("MajorPath(%q, %d) = %s, want %s", tt.path, tt.major, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/lib\n\ngo 1.22\n\nrequire golang.org/x/mod v0.31.0 // indirect\n",
+		"lib.go": "package lib\n",
+		"cmd/tool/main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/lib"
+	util "example.com/lib/internal/util"
+	"example.com/library"
+)
+
+// Keep "example.com/lib" in comments and strings as they are
+const doc = "example.com/lib/internal/util"
+
+func main() { fmt.Println(lib.Name, util.X, library.Y, doc) }
+`,
+		"internal/util/util.go":   "package util\n\nconst X = 1\n",
+		"README.md":               "import example.com/lib\n",
+		"vendor/example.com/x.go": "package x\n\nimport \"example.com/lib\"\n",
+		"testdata/x.go":           "package x\n\nimport \"example.com/lib\"\n",
+		".hidden/x.go":            "package x\n\nimport \"example.com/lib\"\n",
+		"nested/go.mod":           "module example.com/lib/nested\n",
+		"nested/x.go":             "package nested\n\nimport \"example.com/lib\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changed, err := Rewrite(dir, "example.com/lib", "example.com/lib/v2")
+	if err != nil {
+		t.Fatalf("Rewrite failed: %v", err)
+	}
+
+	names := maps.Keys(changed)
+	slices.Sort(names)
+	if want := []string{"cmd/tool/main.go", "go.mod"}; !slices.Equal(names, want) {
+		t.Fatalf("Rewrite changed %q, want %q", names, want)
+	}
+
+	wantMod := "module example.com/lib/v2\n\ngo 1.22\n\nrequire golang.org/x/mod v0.31.0 // indirect\n"
+	if got := string(changed["go.mod"]); got != wantMod {
+		t.Errorf("go.mod =\n%s\nwant\n%s", got, wantMod)
+	}
+
+	wantMain := `package main
+
+import (
+	"fmt"
+
+	"example.com/lib/v2"
+	util "example.com/lib/v2/internal/util"
+	"example.com/library"
+)
+
+// Keep "example.com/lib" in comments and strings as they are
+const doc = "example.com/lib/internal/util"
+
+func main() { fmt.Println(lib.Name, util.X, library.Y, doc) }
+`
+	if got := string(changed["cmd/tool/main.go"]); got != wantMain {
+		t.Errorf("cmd/tool/main.go =\n%s\nwant\n%s", got, wantMain)
+	}
+
+	// Nothing is written
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != files["go.mod"] {
+		t.Errorf("Rewrite wrote go.mod")
+	}
+}
+
+func TestRewriteMajorToMajor(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/lib/v2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source := "package lib\n\nimport (\n\t\"example.com/lib/v2/pkg\"\n\t\"example.com/lib/v20\"\n)\n"
+	if err := os.WriteFile(filepath.Join(dir, "lib.go"), []byte(source), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := Rewrite(dir, "example.com/lib/v2", "example.com/lib/v3")
+	if err != nil {
+		t.Fatalf("Rewrite failed: %v", err)
+	}
+
+	want := "package lib\n\nimport (\n\t\"example.com/lib/v3/pkg\"\n\t\"example.com/lib/v20\"\n)\n"
+	if got := string(changed["lib.go"]); got != want {
+		t.Errorf("lib.go =\n%s\nwant\n%s", got, want)
+	}
+	if got := string(changed["go.mod"]); got != "module example.com/lib/v3\n" {
+		t.Errorf("go.mod = %q, want the v3 module path", got)
+	}
+}
@@ -0,0 +1,242 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// calverToken is a segment of a CalVer format.
+type calverToken struct {
+	pattern string
+	// rank orders the tokens from the year (0) over the month or week (1)
+	// and the day (2) to the micro counter (3).
+	rank int
+	// padded tokens are written with at least two digits.
+	padded bool
+}
+
+// calverTokens are the format tokens of calver.org. Short years count from
+// 2000, so 2026 is 26 for YY and 0Y, and weeks are ISO weeks.
+var calverTokens = map[string]calverToken{
+	"YYYY":  {pattern: `([1-9]\d{3})`, rank: 0},
+	"YY":    {pattern: `(0|[1-9]\d*)`, rank: 0},
+	"0Y":    {pattern: `(\d{2}|[1-9]\d{2,})`, rank: 0, padded: true},
+	"MM":    {pattern: `([1-9]|1[0-2])`, rank: 1},
+	"0M":    {pattern: `(0[1-9]|1[0-2])`, rank: 1, padded: true},
+	"WW":    {pattern: `([1-9]|[1-4]\d|5[0-3])`, rank: 1},
+	"0W":    {pattern: `(0[1-9]|[1-4]\d|5[0-3])`, rank: 1, padded: true},
+	"DD":    {pattern: `([1-9]|[12]\d|3[01])`, rank: 2},
+	"0D":    {pattern: `(0[1-9]|[12]\d|3[01])`, rank: 2, padded: true},
+	"MICRO": {pattern: `(0|[1-9]\d*)`, rank: 3},
+}
+
+// CalVer is calendar versioning: a version starts with the period it was
+// released in, such as 2026.10.3 for the format YYYY.0M.MICRO. Bumping
+// rolls over to the current period, or increments MICRO when the version
+// already belongs to it. Dates are taken in UTC.
+type CalVer struct {
+	format  string
+	tokens  []string
+	pattern *regexp.Regexp
+	weekly  bool
+	micro   bool
+	now     func() time.Time
+}
+
+// NewCalVer returns the CalVer scheme for format, two or three tokens
+// separated by dots from the year down to MICRO, such as YYYY.0M.MICRO,
+// YY.0W.MICRO or YYYY.0M.0D.
+func NewCalVer(format string) (*CalVer, error) {
+	tokens := strings.Split(format, ".")
+	if len(tokens) < 2 || len(tokens) > 3 {
+		return nil, fmt.Errorf("invalid CalVer format %q: must have two or three segments separated by dots", format)
+	}
+
+	c := &CalVer{format: format, tokens: tokens, now: time.Now}
+	patterns := make([]string, len(tokens))
+	month := false
+	for i, name := range tokens {
+		token, ok := calverTokens[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid CalVer format %q: unknown token %s (must be YYYY, YY, 0Y, MM, 0M, WW, 0W, DD, 0D or MICRO)", format, name)
+		}
+		if i == 0 && token.rank != 0 {
+			return nil, fmt.Errorf("invalid CalVer format %q: must start with the year", format)
+		}
+		if i > 0 && token.rank <= calverTokens[tokens[i-1]].rank {
+			return nil, fmt.Errorf("invalid CalVer format %q: segments must go from the year down to MICRO", format)
+		}
+
+		switch name {
+		case "MM", "0M":
+			month = true
+		case "WW", "0W":
+			c.weekly = true
+		case "DD", "0D":
+			if !month {
+				return nil, fmt.Errorf("invalid CalVer format %q: the day requires the month", format)
+			}
+		case "MICRO":
+			c.micro = true
+		}
+		patterns[i] = token.pattern
+	}
+
+	c.pattern = regexp.MustCompile(`^` + strings.Join(patterns, `\.`) + metadataPattern)
+	return c, nil
+}
+
+func (c *CalVer) Name() string {
+	return "calver"
+}
+
+func (c *CalVer) String() string {
+	return c.format
+}
+
+func (c *CalVer) Parse(s string) (*Version, error) {
+	matches := c.pattern.FindStringSubmatch(s)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid version format: %s (expected %s)", s, c.format)
+	}
+
+	values := make([]int, len(c.tokens))
+	for i := range c.tokens {
+		value, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version format: %s (expected %s)", s, c.format)
+		}
+		values[i] = value
+	}
+
+	version := c.version(values)
+	version.Raw = s
+	if prerelease := matches[len(c.tokens)+1]; prerelease != "" {
+		version.Prerelease = strings.Split(prerelease, ".")
+	}
+	if build := matches[len(c.tokens)+2]; build != "" {
+		version.Build = strings.Split(build, ".")
+	}
+
+	return version, nil
+}
+
+func (c *CalVer) Format(v *Version) string {
+	segments := make([]string, len(c.tokens))
+	for i, value := range c.values(v) {
+		if calverTokens[c.tokens[i]].padded {
+			segments[i] = fmt.Sprintf("%02d", value)
+		} else {
+			segments[i] = strconv.Itoa(value)
+		}
+	}
+	return strings.Join(segments, ".") + v.metadata()
+}
+
+// Bump returns the next version. Patch, minor and major all mean the next
+// release, which for a pre-release of the current period is its own
+// release; the pre-release types start or continue a pre-release of it.
+func (c *CalVer) Bump(v *Version, versionType, preid string) (*Version, error) {
+	switch strings.ToLower(versionType) {
+	case "patch", "minor", "major":
+		if v.IsPrerelease() {
+			current := c.period(c.now())
+			if compareValues(c.values(v.core())[:len(current)], current) == 0 {
+				return v.Release()
+			}
+		}
+		return c.next(v)
+	case "prepatch", "preminor", "premajor":
+		next, err := c.next(v)
+		if err != nil {
+			return nil, err
+		}
+		return next.startPrerelease(preid)
+	case "prerelease":
+		if !v.IsPrerelease() {
+			return c.Bump(v, "prepatch", preid)
+		}
+		return v.BumpPrerelease(preid)
+	case "release", "promote":
+		return v.Release()
+	default:
+		return nil, fmt.Errorf("invalid version type: %s (must be patch, minor, major, prepatch, preminor, premajor, prerelease or release)", versionType)
+	}
+}
+
+// next returns the first version of the current period, or the version
+// after v when v already belongs to the current period.
+func (c *CalVer) next(v *Version) (*Version, error) {
+	current := c.period(c.now())
+	values := c.values(v)
+
+	switch compareValues(values[:len(current)], current) {
+	case 0:
+		if !c.micro {
+			return nil, fmt.Errorf("%s already is the version of the current period; add MICRO to the CalVer format to release more than once per period", c.Format(v))
+		}
+		values[len(current)]++
+	case 1:
+		return nil, fmt.Errorf("version %s is ahead of the current date", c.Format(v))
+	default:
+		values = current
+		if c.micro {
+			values = append(values, 0)
+		}
+	}
+
+	return c.version(values), nil
+}
+
+// period returns the values of the date tokens for t.
+func (c *CalVer) period(t time.Time) []int {
+	t = t.UTC()
+	year, week := t.ISOWeek()
+	if !c.weekly {
+		year = t.Year()
+	}
+
+	var values []int
+	for _, token := range c.tokens {
+		switch token {
+		case "YYYY":
+			values = append(values, year)
+		case "YY", "0Y":
+			values = append(values, year-2000)
+		case "MM", "0M":
+			values = append(values, int(t.Month()))
+		case "WW", "0W":
+			values = append(values, week)
+		case "DD", "0D":
+			values = append(values, t.Day())
+		}
+	}
+	return values
+}
+
+// values returns the segments of v, which are stored in Major, Minor and
+// Patch in order.
+func (c *CalVer) values(v *Version) []int {
+	return []int{v.Major, v.Minor, v.Patch}[:len(c.tokens)]
+}
+
+func (c *CalVer) version(values []int) *Version {
+	v := &Version{scheme: c}
+	segments := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, value := range values {
+		*segments[i] = value
+	}
+	return v
+}
+
+func compareValues(a, b []int) int {
+	for i := range a {
+		if c := compareInt(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+package version
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewCalVer(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{format: "YYYY.0M.MICRO"},
+		{format: "YY.MM.MICRO"},
+		{format: "0Y.0W.MICRO"},
+		{format: "YYYY.0M.0D"},
+		{format: "YYYY.MICRO"},
+		{format: "YYYY", wantErr: true},
+		{format: "YYYY.0M.0D.MICRO", wantErr: true},
+		{format: "0M.YYYY.MICRO", wantErr: true},
+		{format: "YYYY.MICRO.0M", wantErr: true},
+		{format: "YYYY.0D.MICRO", wantErr: true},
+		{format: "YYYY.0M.XX", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			_, err := NewCalVer(tt.format)
+			if tt.wantErr && err == nil {
+				t.Fatalf("NewCalVer(%q) succeeded, want an error", tt.format)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("NewCalVer(%q) failed: %v", tt.format, err)
+			}
+		})
+	}
+}
+
+func TestCalVerParseFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{format: "YYYY.0M.MICRO", input: "2026.10.3", want: "2026.10.3"},
+		{format: "YYYY.0M.MICRO", input: "2026.01.0-rc.1+build.5", want: "2026.01.0-rc.1+build.5"},
+		{format: "YYYY.MM.MICRO", input: "2026.1.0", want: "2026.1.0"},
+		{format: "0Y.0M.MICRO", input: "06.01.2", want: "06.01.2"},
+		{format: "YY.0W.MICRO", input: "26.42.0", want: "26.42.0"},
+		{format: "YYYY.0M.MICRO", input: "2026.1.0", wantErr: true},
+		{format: "YYYY.MM.MICRO", input: "2026.01.0", wantErr: true},
+		{format: "YYYY.0M.MICRO", input: "2026.13.0", wantErr: true},
+		{format: "YYYY.0M.MICRO", input: "v2026.10.0", wantErr: true},
+		{format: "YYYY.0M.MICRO", input: "2026.10", wantErr: true},
+		{format: "YYYY.0M.0D", input: "2026.10.32", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.input, func(t *testing.T) {
+			v, err := mustCalVer(t, tt.format, time.Time{}).Parse(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %v, want an error", tt.input, v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+			}
+			if got := v.String(); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalVerBump(t *testing.T) {
+	today := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		format      string
+		now         time.Time
+		version     string
+		versionType string
+		preid       string
+		want        string
+		wantErr     bool
+	}{
+		// Period rollover
+		{name: "rolls over to the current month", format: "YYYY.0M.MICRO", version: "2026.09.3", versionType: "patch", want: "2026.10.0"},
+		{name: "rolls over to the current year", format: "YYYY.0M.MICRO", version: "2025.12.5", versionType: "major", want: "2026.10.0"},
+		{name: "rolls over to the current day", format: "YYYY.0M.0D", version: "2026.10.16", versionType: "patch", want: "2026.10.17"},
+		{name: "rolls over to the current week", format: "YY.0W.MICRO", version: "26.41.2", versionType: "minor", want: "26.42.0"},
+		{name: "starts at the current period", format: "YYYY.0M.MICRO", version: "0.0.0", versionType: "patch", want: "2026.10.0"},
+		{name: "uses the ISO year of the week", format: "YY.0W.MICRO", now: time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), version: "26.52.1", versionType: "patch", want: "26.53.0"},
+		{name: "uses UTC", format: "YYYY.0M.MICRO", now: time.Date(2026, time.November, 1, 0, 30, 0, 0, time.FixedZone("CET", 3600)), version: "2026.10.1", versionType: "patch", want: "2026.10.2"},
+
+		// MICRO increments
+		{name: "increments MICRO in the current period", format: "YYYY.0M.MICRO", version: "2026.10.3", versionType: "patch", want: "2026.10.4"},
+		{name: "treats minor like patch", format: "YYYY.0M.MICRO", version: "2026.10.3", versionType: "minor", want: "2026.10.4"},
+		{name: "treats major like patch", format: "YYYY.0M.MICRO", version: "2026.10.3", versionType: "major", want: "2026.10.4"},
+		{name: "increments MICRO without the month", format: "YYYY.MICRO", version: "2026.7", versionType: "patch", want: "2026.8"},
+		{name: "needs MICRO for a second release", format: "YYYY.0M.0D", version: "2026.10.17", versionType: "patch", wantErr: true},
+
+		// Version ahead of today
+		{name: "rejects a version of a later month", format: "YYYY.0M.MICRO", version: "2026.11.0", versionType: "patch", wantErr: true},
+		{name: "rejects a version of a later year", format: "YYYY.0M.MICRO", version: "2027.01.0", versionType: "prepatch", wantErr: true},
+		{name: "rejects a version of a later day", format: "YYYY.0M.0D", version: "2026.10.18", versionType: "patch", wantErr: true},
+
+		// Pre-releases
+		{name: "releases a pre-release of the current period", format: "YYYY.0M.MICRO", version: "2026.10.4-rc.2", versionType: "patch", want: "2026.10.4"},
+		{name: "releases a pre-release of the current period on minor", format: "YYYY.0M.MICRO", version: "2026.10.4-rc.2", versionType: "minor", want: "2026.10.4"},
+		{name: "releases a pre-release of the current day", format: "YYYY.0M.0D", version: "2026.10.17-beta.1", versionType: "major", want: "2026.10.17"},
+		{name: "rolls a pre-release of an earlier period over", format: "YYYY.0M.MICRO", version: "2026.09.1-rc.1", versionType: "patch", want: "2026.10.0"},
+		{name: "starts a pre-release of the next version", format: "YYYY.0M.MICRO", version: "2026.10.3", versionType: "prepatch", want: "2026.10.4-rc.1"},
+		{name: "starts a pre-release of the current period", format: "YYYY.0M.MICRO", version: "2026.09.3", versionType: "preminor", preid: "beta", want: "2026.10.0-beta.1"},
+		{name: "starts a pre-release on prerelease", format: "YYYY.0M.MICRO", version: "2026.10.3", versionType: "prerelease", want: "2026.10.4-rc.1"},
+		{name: "continues a pre-release", format: "YYYY.0M.MICRO", version: "2026.10.4-rc.1", versionType: "prerelease", want: "2026.10.4-rc.2"},
+		{name: "finalises a pre-release", format: "YYYY.0M.MICRO", version: "2026.09.1-rc.3", versionType: "release", want: "2026.09.1"},
+		{name: "rejects release of a final version", format: "YYYY.0M.MICRO", version: "2026.10.1", versionType: "release", wantErr: true},
+		{name: "rejects an unknown type", format: "YYYY.0M.MICRO", version: "2026.10.1", versionType: "micro", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := tt.now
+			if now.IsZero() {
+				now = today
+			}
+			c := mustCalVer(t, tt.format, now)
+
+			v := Zero(c)
+			if tt.version != "0.0.0" {
+				var err error
+				if v, err = c.Parse(tt.version); err != nil {
+					t.Fatalf("Parse(%q) failed: %v", tt.version, err)
+				}
+			}
+
+			got, err := v.Bump(tt.versionType, tt.preid)
+			checkBump(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+// mustCalVer returns the CalVer scheme for format with the clock stopped
+// at now.
+func mustCalVer(t *testing.T, format string, now time.Time) *CalVer {
+	t.Helper()
+	c, err := NewCalVer(format)
+	if err != nil {
+		t.Fatalf("NewCalVer(%q) failed: %v", format, err)
+	}
+	c.now = func() time.Time { return now }
+	return c
+}
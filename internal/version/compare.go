@@ -0,0 +1,120 @@
+package version
+
+import (
+	"sort"
+	"strings"
+)
+
+// Compare returns -1, 0 or 1 depending on whether v has lower, equal or
+// higher precedence than other. Build metadata is ignored, as required by
+// the SemVer 2.0 specification.
+func (v *Version) Compare(other *Version) int {
+	if c := compareInt(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+func (v *Version) Less(other *Version) bool {
+	return v.Compare(other) < 0
+}
+
+func (v *Version) Equal(other *Version) bool {
+	return v.Compare(other) == 0
+}
+
+// Sort orders versions by ascending precedence.
+func Sort(versions []*Version) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Less(versions[j])
+	})
+}
+
+// Latest returns the version with the highest precedence, or nil when
+// versions is empty.
+func Latest(versions []*Version) *Version {
+	var latest *Version
+	for _, v := range versions {
+		if latest == nil || latest.Less(v) {
+			latest = v
+		}
+	}
+	return latest
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparePrerelease implements rule 11 of the specification: a version
+// without pre-release identifiers has higher precedence, numeric identifiers
+// compare numerically and always sort below alphanumeric ones, and a longer
+// set of identifiers wins when all preceding ones are equal.
+func comparePrerelease(a, b []string) int {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	if len(a) == 0 {
+		return 1
+	}
+	if len(b) == 0 {
+		return -1
+	}
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(a), len(b))
+}
+
+func compareIdentifier(a, b string) int {
+	aNumeric := isNumeric(a)
+	bNumeric := isNumeric(b)
+
+	switch {
+	case aNumeric && bNumeric:
+		// Numeric identifiers have no leading zeros, so the longer one is
+		// larger and equal lengths compare lexically without overflow.
+		if c := compareInt(len(a), len(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Placeholder marks the position of the version in a tag format.
+const Placeholder = "{version}"
+
+// TagFormat describes version tags as a prefix and a suffix around the
+// version, such as "v{version}" for v1.2.3 or "release-{version}-final" for
+// release-1.2.3-final.
+type TagFormat struct {
+	Prefix string
+	Suffix string
+	// Scheme writes and parses the version; nil means SemVer.
+	Scheme Scheme
+}
+
+// ParseTagFormat parses a format that contains Placeholder exactly once.
+func ParseTagFormat(format string) (TagFormat, error) {
+	if strings.Count(format, Placeholder) != 1 {
+		return TagFormat{}, fmt.Errorf("invalid tag format %q: must contain %s exactly once", format, Placeholder)
+	}
+
+	prefix, suffix, _ := strings.Cut(format, Placeholder)
+	return TagFormat{Prefix: prefix, Suffix: suffix}, nil
+}
+
+func (f TagFormat) String() string {
+	return f.Prefix + Placeholder + f.Suffix
+}
+
+// Tag returns the tag for v.
+func (f TagFormat) Tag(v *Version) string {
+	return f.Prefix + f.scheme().Format(v) + f.Suffix
+}
+
+// Parse parses a tag in this format. The version must not have its own 'v',
+// so "v1.2.3" matches "v{version}" but not "{version}". Raw is set to the
+// whole tag.
+func (f TagFormat) Parse(tag string) (*Version, error) {
+	if !strings.HasPrefix(tag, f.Prefix) || !strings.HasSuffix(tag, f.Suffix) || len(tag) < len(f.Prefix)+len(f.Suffix) {
+		return nil, fmt.Errorf("tag %s does not match format %s", tag, f)
+	}
+
+	rest := strings.TrimSuffix(strings.TrimPrefix(tag, f.Prefix), f.Suffix)
+	if strings.HasPrefix(rest, "v") {
+		return nil, fmt.Errorf("invalid version format: %s", tag)
+	}
+
+	version, err := f.scheme().Parse(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version format: %s", tag)
+	}
+	version.Raw = tag
+
+	return version, nil
+}
+
+func (f TagFormat) scheme() Scheme {
+	if f.Scheme == nil {
+		return SemVer{}
+	}
+	return f.Scheme
+}
@@ -0,0 +1,35 @@
+package version
+
+// Scheme is a versioning scheme: how versions are written, parsed and
+// bumped. Versions of every scheme share the Version type, so they are
+// ordered the same way, by their numeric parts and then by pre-release.
+type Scheme interface {
+	// Name identifies the scheme, such as "semver" or "calver".
+	Name() string
+	// Parse parses a version without tag prefix or suffix.
+	Parse(s string) (*Version, error)
+	// Format writes v without tag prefix or suffix.
+	Format(v *Version) string
+	// Bump returns the version that follows v for the bump type, such as
+	// patch, minor, major, prerelease or release.
+	Bump(v *Version, versionType, preid string) (*Version, error)
+}
+
+// SemVer is Semantic Versioning 2.0, the default scheme.
+type SemVer struct{}
+
+func (SemVer) Name() string {
+	return "semver"
+}
+
+func (SemVer) Parse(s string) (*Version, error) {
+	return Parse(s)
+}
+
+func (SemVer) Format(v *Version) string {
+	return v.SemVer()
+}
+
+func (SemVer) Bump(v *Version, versionType, preid string) (*Version, error) {
+	return v.bumpSemVer(versionType, preid)
+}
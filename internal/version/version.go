@@ -7,18 +7,44 @@ import (
 	"strings"
 )
 
+// metadataPattern matches the optional pre-release and build metadata that
+// follow the numeric part of a version.
+const metadataPattern = `(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`
+
+// semverPattern follows the SemVer 2.0 grammar, with an optional leading 'v'.
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` + metadataPattern)
+
 type Version struct {
-	Major int
-	Minor int
-	Patch int
-	Raw   string
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+	Build      []string
+	Raw        string
+
+	// scheme is the versioning scheme the version belongs to; nil means
+	// SemVer.
+	scheme Scheme
+}
+
+// Zero returns the version that precedes the first release in scheme.
+func Zero(scheme Scheme) *Version {
+	return &Version{scheme: scheme}
+}
+
+// Scheme returns the versioning scheme of the version.
+func (v *Version) Scheme() Scheme {
+	if v.scheme == nil {
+		return SemVer{}
+	}
+	return v.scheme
 }
 
 func Parse(versionStr string) (*Version, error) {
-	re := regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)`)
-	matches := re.FindStringSubmatch(versionStr)
+	matches := semverPattern.FindStringSubmatch(versionStr)
 
-	if len(matches) != 4 {
+	if len(matches) != 6 {
 		return nil, fmt.Errorf("invalid version format: %s", versionStr)
 	}
 
@@ -37,19 +63,58 @@ func Parse(versionStr string) (*Version, error) {
 		return nil, fmt.Errorf("invalid patch version: %s", matches[3])
 	}
 
-	return &Version{
+	version := &Version{
 		Major: major,
 		Minor: minor,
 		Patch: patch,
 		Raw:   versionStr,
-	}, nil
+	}
+
+	if matches[4] != "" {
+		version.Prerelease = strings.Split(matches[4], ".")
+	}
+	if matches[5] != "" {
+		version.Build = strings.Split(matches[5], ".")
+	}
+
+	return version, nil
 }
 
+// String returns the version in its scheme without any prefix. Use a
+// TagFormat for tags.
 func (v *Version) String() string {
-	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+	return v.Scheme().Format(v)
 }
 
+// SemVer returns the version without any prefix, such as 1.2.3-rc.1.
+func (v *Version) SemVer() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch) + v.metadata()
+}
+
+// metadata returns the pre-release and build metadata suffix, such as
+// -rc.1+build.5.
+func (v *Version) metadata() string {
+	s := ""
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// IsPrerelease reports whether the version carries pre-release identifiers.
+func (v *Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// BumpPatch returns the next patch version. A pre-release is finalised
+// instead, as 1.2.3-rc.1 already precedes 1.2.3.
 func (v *Version) BumpPatch() *Version {
+	if v.IsPrerelease() {
+		return v.core()
+	}
 	return &Version{
 		Major: v.Major,
 		Minor: v.Minor,
@@ -57,7 +122,12 @@ func (v *Version) BumpPatch() *Version {
 	}
 }
 
+// BumpMinor returns the next minor version. A pre-release of a minor
+// version, such as 1.3.0-rc.1, is finalised into 1.3.0 instead.
 func (v *Version) BumpMinor() *Version {
+	if v.IsPrerelease() && v.Patch == 0 {
+		return v.core()
+	}
 	return &Version{
 		Major: v.Major,
 		Minor: v.Minor + 1,
@@ -65,7 +135,12 @@ func (v *Version) BumpMinor() *Version {
 	}
 }
 
+// BumpMajor returns the next major version. A pre-release of a major
+// version, such as 2.0.0-rc.3, is finalised into 2.0.0 instead.
 func (v *Version) BumpMajor() *Version {
+	if v.IsPrerelease() && v.Minor == 0 && v.Patch == 0 {
+		return v.core()
+	}
 	return &Version{
 		Major: v.Major + 1,
 		Minor: 0,
@@ -73,7 +148,65 @@ func (v *Version) BumpMajor() *Version {
 	}
 }
 
-func (v *Version) Bump(versionType string) (*Version, error) {
+// BumpPrepatch starts a pre-release of the next patch version.
+func (v *Version) BumpPrepatch(preid string) (*Version, error) {
+	return v.core().BumpPatch().startPrerelease(preid)
+}
+
+// BumpPreminor starts a pre-release of the next minor version.
+func (v *Version) BumpPreminor(preid string) (*Version, error) {
+	return v.core().BumpMinor().startPrerelease(preid)
+}
+
+// BumpPremajor starts a pre-release of the next major version.
+func (v *Version) BumpPremajor(preid string) (*Version, error) {
+	return v.core().BumpMajor().startPrerelease(preid)
+}
+
+// DefaultPreid is the channel of a new pre-release when none is given.
+const DefaultPreid = "rc"
+
+// BumpPrerelease increments the counter of an existing pre-release
+// (v1.2.0-rc.1 → v1.2.0-rc.2). When preid names a different channel the
+// counter restarts on that channel, and a version that is not a pre-release
+// yet starts one on the next patch version.
+func (v *Version) BumpPrerelease(preid string) (*Version, error) {
+	if !v.IsPrerelease() {
+		return v.BumpPrepatch(preid)
+	}
+
+	channel, counter := v.prereleaseChannel()
+	if preid == "" || preid == strings.Join(channel, ".") {
+		next := v.core()
+		next.Prerelease = append(append([]string{}, channel...), strconv.Itoa(counter+1))
+		return next, nil
+	}
+
+	next, err := v.core().startPrerelease(preid)
+	if err != nil {
+		return nil, err
+	}
+	if !v.Less(next) {
+		return nil, fmt.Errorf("pre-release %s would not be newer than %s", next.String(), v.String())
+	}
+	return next, nil
+}
+
+// Release strips the pre-release identifiers, finalising v2.0.0-rc.3 into
+// v2.0.0.
+func (v *Version) Release() (*Version, error) {
+	if !v.IsPrerelease() {
+		return nil, fmt.Errorf("version %s is not a pre-release", v.String())
+	}
+	return v.core(), nil
+}
+
+// Bump returns the next version for versionType in the scheme of v.
+func (v *Version) Bump(versionType, preid string) (*Version, error) {
+	return v.Scheme().Bump(v, versionType, preid)
+}
+
+func (v *Version) bumpSemVer(versionType, preid string) (*Version, error) {
 	switch strings.ToLower(versionType) {
 	case "patch":
 		return v.BumpPatch(), nil
@@ -81,9 +214,56 @@ func (v *Version) Bump(versionType string) (*Version, error) {
 		return v.BumpMinor(), nil
 	case "major":
 		return v.BumpMajor(), nil
+	case "prepatch":
+		return v.BumpPrepatch(preid)
+	case "preminor":
+		return v.BumpPreminor(preid)
+	case "premajor":
+		return v.BumpPremajor(preid)
+	case "prerelease":
+		return v.BumpPrerelease(preid)
+	case "release", "promote":
+		return v.Release()
 	default:
-		return nil, fmt.Errorf("invalid version type: %s (must be patch, minor, or major)", versionType)
+		return nil, fmt.Errorf("invalid version type: %s (must be patch, minor, major, prepatch, preminor, premajor, prerelease or release)", versionType)
+	}
+}
+
+// core returns a copy of the version without pre-release or build metadata.
+func (v *Version) core() *Version {
+	return &Version{
+		Major:  v.Major,
+		Minor:  v.Minor,
+		Patch:  v.Patch,
+		scheme: v.scheme,
+	}
+}
+
+// startPrerelease starts the first pre-release of v on the preid channel,
+// or on DefaultPreid when no channel is given.
+func (v *Version) startPrerelease(preid string) (*Version, error) {
+	if preid == "" {
+		preid = DefaultPreid
+	}
+	if _, err := Parse("0.0.0-" + preid); err != nil {
+		return nil, fmt.Errorf("invalid pre-release identifier: %s", preid)
+	}
+
+	next := v.core()
+	next.Prerelease = append(strings.Split(preid, "."), "1")
+	return next, nil
+}
+
+// prereleaseChannel splits the pre-release into its channel and trailing
+// counter, so rc.2 yields ("rc", 2) and beta yields ("beta", 0).
+func (v *Version) prereleaseChannel() ([]string, int) {
+	last := len(v.Prerelease) - 1
+	if isNumeric(v.Prerelease[last]) {
+		if counter, err := strconv.Atoi(v.Prerelease[last]); err == nil {
+			return v.Prerelease[:last], counter
+		}
 	}
+	return v.Prerelease, 0
 }
 
 func NewFromString(versionStr string) *Version {
@@ -0,0 +1,276 @@
+package version
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input      string
+		major      int
+		minor      int
+		patch      int
+		prerelease []string
+		build      []string
+		wantErr    bool
+	}{
+		{input: "1.2.3", major: 1, minor: 2, patch: 3},
+		{input: "v1.2.3", major: 1, minor: 2, patch: 3},
+		{input: "0.0.0", major: 0, minor: 0, patch: 0},
+		{input: "1.0.0-alpha", major: 1, prerelease: []string{"alpha"}},
+		{input: "1.0.0-alpha.1", major: 1, prerelease: []string{"alpha", "1"}},
+		{input: "1.0.0-0.3.7", major: 1, prerelease: []string{"0", "3", "7"}},
+		{input: "1.0.0-x-y-z.--", major: 1, prerelease: []string{"x-y-z", "--"}},
+		{input: "1.0.0+20130313144700", major: 1, build: []string{"20130313144700"}},
+		{input: "1.0.0-beta+exp.sha.5114f85", major: 1, prerelease: []string{"beta"}, build: []string{"exp", "sha", "5114f85"}},
+		{input: "1.0.0-rc.1+build.007", major: 1, prerelease: []string{"rc", "1"}, build: []string{"build", "007"}},
+		{input: "", wantErr: true},
+		{input: "1.2", wantErr: true},
+		{input: "1.2.3.4", wantErr: true},
+		{input: "01.2.3", wantErr: true},
+		{input: "1.02.3", wantErr: true},
+		{input: "1.2.03", wantErr: true},
+		{input: "V1.2.3", wantErr: true},
+		{input: "vv1.2.3", wantErr: true},
+		{input: "1.2.3-", wantErr: true},
+		{input: "1.2.3-01", wantErr: true},
+		{input: "1.2.3-rc..1", wantErr: true},
+		{input: "1.2.3+", wantErr: true},
+		{input: "1.2.3-rc_1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := Parse(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %v, want an error", tt.input, v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+			}
+			if v.Major != tt.major || v.Minor != tt.minor || v.Patch != tt.patch {
+				t.Errorf("Parse(%q) = %d.%d.%d, want %d.%d.%d", tt.input, v.Major, v.Minor, v.Patch, tt.major, tt.minor, tt.patch)
+			}
+			if !slices.Equal(v.Prerelease, tt.prerelease) {
+				t.Errorf("Parse(%q) pre-release = %q, want %q", tt.input, v.Prerelease, tt.prerelease)
+			}
+			if !slices.Equal(v.Build, tt.build) {
+				t.Errorf("Parse(%q) build = %q, want %q", tt.input, v.Build, tt.build)
+			}
+			if v.Raw != tt.input {
+				t.Errorf("Parse(%q) raw = %q, want the input", tt.input, v.Raw)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// Precedence examples from §11 of the SemVer 2.0 specification, in
+	// ascending order
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"2.0.0",
+		"2.1.0",
+		"2.1.1",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			a, b := mustParse(t, ordered[i]), mustParse(t, ordered[j])
+			want := compareInt(i, j)
+			if got := a.Compare(b); got != want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		// Build metadata is ignored
+		{a: "1.0.0+build.1", b: "1.0.0+build.2", want: 0},
+		{a: "1.0.0-rc.1+a", b: "1.0.0-rc.1", want: 0},
+		// The 'v' prefix is not part of the version
+		{a: "v1.2.3", b: "1.2.3", want: 0},
+		// Numbers compare numerically, not lexically
+		{a: "1.10.0", b: "1.9.0", want: 1},
+		{a: "1.0.0-rc.10", b: "1.0.0-rc.9", want: 1},
+		// Numeric identifiers are lower than alphanumeric ones
+		{a: "1.0.0-1", b: "1.0.0-alpha", want: -1},
+		// More identifiers are higher when the others are equal
+		{a: "1.0.0-alpha.1.1", b: "1.0.0-alpha.1", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := mustParse(t, tt.a).Compare(mustParse(t, tt.b)); got != tt.want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		version     string
+		versionType string
+		preid       string
+		want        string
+		wantErr     bool
+	}{
+		{version: "1.2.3", versionType: "patch", want: "1.2.4"},
+		{version: "1.2.3", versionType: "minor", want: "1.3.0"},
+		{version: "1.2.3", versionType: "major", want: "2.0.0"},
+		{version: "1.2.3", versionType: "MAJOR", want: "2.0.0"},
+		{version: "1.2.3-rc.1+build.5", versionType: "patch", want: "1.2.3"},
+		{version: "1.3.0-rc.1", versionType: "patch", want: "1.3.0"},
+		{version: "1.3.0-rc.1", versionType: "minor", want: "1.3.0"},
+		{version: "1.3.1-rc.1", versionType: "minor", want: "1.4.0"},
+		{version: "1.3.0-rc.1", versionType: "major", want: "2.0.0"},
+		{version: "2.0.0-rc.3", versionType: "major", want: "2.0.0"},
+		{version: "2.0.0-rc.3", versionType: "minor", want: "2.0.0"},
+		{version: "2.0.0-rc.3", versionType: "prepatch", want: "2.0.1-rc.1"},
+		{version: "2.0.0-rc.3", versionType: "premajor", want: "3.0.0-rc.1"},
+		{version: "1.2.3", versionType: "prepatch", want: "1.2.4-rc.1"},
+		{version: "1.2.3", versionType: "preminor", preid: "beta", want: "1.3.0-beta.1"},
+		{version: "1.2.3", versionType: "premajor", preid: "alpha", want: "2.0.0-alpha.1"},
+		{version: "1.2.3", versionType: "premajor", preid: "rc_1", wantErr: true},
+		{version: "1.2.3", versionType: "prerelease", want: "1.2.4-rc.1"},
+		{version: "1.2.3", versionType: "prerelease", preid: "beta", want: "1.2.4-beta.1"},
+		{version: "2.0.0-rc.3", versionType: "release", want: "2.0.0"},
+		{version: "2.0.0-rc.3+build.1", versionType: "promote", want: "2.0.0"},
+		{version: "2.0.0", versionType: "release", wantErr: true},
+		{version: "1.2.3", versionType: "micro", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.versionType, func(t *testing.T) {
+			got, err := mustParse(t, tt.version).Bump(tt.versionType, tt.preid)
+			checkBump(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestBumpPrerelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		preid   string
+		want    string
+		wantErr bool
+	}{
+		{name: "keeps the channel without preid", version: "1.2.0-rc.1", want: "1.2.0-rc.2"},
+		{name: "keeps the channel with the same preid", version: "1.2.0-rc.1", preid: "rc", want: "1.2.0-rc.2"},
+		{name: "keeps a dotted channel", version: "1.2.0-beta.x.4", want: "1.2.0-beta.x.5"},
+		{name: "numbers a channel without counter", version: "1.2.0-beta", want: "1.2.0-beta.1"},
+		{name: "counts numerically", version: "1.2.0-rc.9", want: "1.2.0-rc.10"},
+		{name: "drops build metadata", version: "1.2.0-rc.1+build.7", want: "1.2.0-rc.2"},
+		{name: "switches to a higher channel", version: "1.2.0-alpha.3", preid: "beta", want: "1.2.0-beta.1"},
+		{name: "switches to rc", version: "1.2.0-beta.2", preid: "rc", want: "1.2.0-rc.1"},
+		{name: "rejects a lower channel", version: "1.2.0-beta.2", preid: "alpha", wantErr: true},
+		{name: "rejects an invalid preid", version: "1.2.0-beta.2", preid: "rc!", wantErr: true},
+		{name: "starts on the next patch of a final version", version: "1.2.0", want: "1.2.1-rc.1"},
+		{name: "starts on the preid channel of a final version", version: "1.2.0", preid: "alpha", want: "1.2.1-alpha.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mustParse(t, tt.version).BumpPrerelease(tt.preid)
+			checkBump(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestTagFormatParse(t *testing.T) {
+	tests := []struct {
+		format  string
+		tag     string
+		want    string
+		wantErr bool
+	}{
+		{format: "v{version}", tag: "v1.2.3", want: "1.2.3"},
+		{format: "v{version}", tag: "v1.2.3-rc.1+build.5", want: "1.2.3-rc.1+build.5"},
+		{format: "{version}", tag: "1.2.3", want: "1.2.3"},
+		{format: "api@{version}", tag: "api@0.4.0", want: "0.4.0"},
+		{format: "release-{version}-final", tag: "release-1.2.3-final", want: "1.2.3"},
+		{format: "v{version}", tag: "1.2.3", wantErr: true},
+		{format: "v{version}", tag: "vv1.2.3", wantErr: true},
+		{format: "{version}", tag: "v1.2.3", wantErr: true},
+		{format: "api@{version}", tag: "web@1.2.3", wantErr: true},
+		{format: "release-{version}-final", tag: "release-1.2.3", wantErr: true},
+		{format: "v{version}", tag: "v1.2", wantErr: true},
+		{format: "v{version}", tag: "v", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.tag, func(t *testing.T) {
+			f, err := ParseTagFormat(tt.format)
+			if err != nil {
+				t.Fatalf("ParseTagFormat(%q) failed: %v", tt.format, err)
+			}
+
+			v, err := f.Parse(tt.tag)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %v, want an error", tt.tag, v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.tag, err)
+			}
+			if got := v.String(); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.tag, got, tt.want)
+			}
+			if v.Raw != tt.tag {
+				t.Errorf("Parse(%q) raw = %q, want the tag", tt.tag, v.Raw)
+			}
+			if got := f.Tag(v); got != tt.tag {
+				t.Errorf("Tag(Parse(%q)) = %s, want the tag", tt.tag, got)
+			}
+		})
+	}
+
+	for _, format := range []string{"v", "{version}-{version}", ""} {
+		if _, err := ParseTagFormat(format); err == nil {
+			t.Errorf("ParseTagFormat(%q) succeeded, want an error", format)
+		}
+	}
+}
+
+func mustParse(t *testing.T, s string) *Version {
+	t.Helper()
+	v, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", s, err)
+	}
+	return v
+}
+
+func checkBump(t *testing.T, got *Version, err error, want string, wantErr bool) {
+	t.Helper()
+	if wantErr {
+		if err == nil {
+			t.Fatalf("got %s, want an error", got)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("bump failed: %v", err)
+	}
+	if got.String() != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
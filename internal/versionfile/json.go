@@ -0,0 +1,94 @@
+package versionfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// jsonFrame is an open object or array while scanning a JSON document.
+type jsonFrame struct {
+	object bool
+	// key is the key of the current member; expectKey is set between
+	// members.
+	key       string
+	expectKey bool
+}
+
+// replaceJSON replaces the string at the dotted key, found by scanning the
+// tokens so that formatting and member order stay as they are.
+func replaceJSON(data []byte, key, newVersion string) ([]byte, string, error) {
+	path := strings.Split(key, ".")
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	var frames []jsonFrame
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, "", fmt.Errorf("key %s not found", key)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		if n := len(frames); n > 0 && frames[n-1].object && frames[n-1].expectKey {
+			if name, ok := token.(string); ok {
+				frames[n-1].key = name
+				frames[n-1].expectKey = false
+				continue
+			}
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			frames = append(frames, jsonFrame{object: token == json.Delim('{'), expectKey: true})
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			frames = frames[:len(frames)-1]
+		default:
+			if jsonPathIs(frames, path) {
+				value, ok := token.(string)
+				if !ok {
+					return nil, "", fmt.Errorf("%s is not a string", key)
+				}
+				end := int(decoder.InputOffset())
+				start := int(offset) + bytes.IndexByte(data[offset:end], '"')
+				encoded, err := json.Marshal(newVersion)
+				if err != nil {
+					return nil, "", err
+				}
+				return splice(data, start, end, string(encoded)), value, nil
+			}
+		}
+
+		if n := len(frames); n > 0 && frames[n-1].object {
+			frames[n-1].expectKey = true
+		}
+	}
+}
+
+// jsonPathIs reports whether the value being read is at path, which only
+// goes through objects.
+func jsonPathIs(frames []jsonFrame, path []string) bool {
+	if len(frames) != len(path) {
+		return false
+	}
+	for i, frame := range frames {
+		if !frame.object || frame.key != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splice returns data with data[start:end] replaced by s.
+func splice(data []byte, start, end int, s string) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(s))
+	out = append(out, data[:start]...)
+	out = append(out, s...)
+	return append(out, data[end:]...)
+}
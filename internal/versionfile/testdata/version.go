@@ -0,0 +1,10 @@
+// Package build records the build information.
+package build
+
+var (
+	// Version is set on release.
+	Version = "1.4.2"
+	Commit  = "unknown"
+)
+
+const name, Release = "web", `1.4.2`
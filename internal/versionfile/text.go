@@ -0,0 +1,94 @@
+package versionfile
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+)
+
+// replaceGo replaces the string literal assigned to the constant or
+// variable name, such as const Version = "1.2.3".
+func replaceGo(filename string, data []byte, name, newVersion string) ([]byte, string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, data, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || (gen.Tok != token.CONST && gen.Tok != token.VAR) {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			for i, ident := range value.Names {
+				if ident.Name != name {
+					continue
+				}
+				if i >= len(value.Values) {
+					return nil, "", fmt.Errorf("%s has no value", name)
+				}
+				lit, ok := value.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return nil, "", fmt.Errorf("%s is not a string literal", name)
+				}
+				old, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return nil, "", err
+				}
+
+				replacement := strconv.Quote(newVersion)
+				if lit.Value[0] == '`' {
+					replacement = "`" + newVersion + "`"
+				}
+				start := fset.Position(lit.Pos()).Offset
+				end := fset.Position(lit.End()).Offset
+				return splice(data, start, end, replacement), old, nil
+			}
+		}
+	}
+
+	return nil, "", fmt.Errorf("no constant or variable %s", name)
+}
+
+// replaceRegex replaces the capture group of every match of pattern.
+func replaceRegex(data []byte, pattern, newVersion string) ([]byte, string, error) {
+	re := regexp.MustCompile(pattern)
+	matches := re.FindAllSubmatchIndex(data, -1)
+
+	var out []byte
+	old := ""
+	last := 0
+	for _, match := range matches {
+		if match[2] < 0 {
+			continue
+		}
+		if out == nil {
+			old = string(data[match[2]:match[3]])
+		}
+		out = append(out, data[last:match[2]]...)
+		out = append(out, newVersion...)
+		last = match[3]
+	}
+	if out == nil {
+		return nil, "", fmt.Errorf("pattern %q does not match", pattern)
+	}
+
+	return append(out, data[last:]...), old, nil
+}
+
+// replacePlain replaces a file that holds nothing but the version, keeping
+// its trailing newline.
+func replacePlain(data []byte, newVersion string) ([]byte, string, error) {
+	old := string(bytes.TrimSpace(data))
+	suffix := data[len(bytes.TrimRight(data, " \t\r\n")):]
+	if len(data) == 0 {
+		suffix = []byte("\n")
+	}
+	return append([]byte(newVersion), suffix...), old, nil
+}
@@ -0,0 +1,79 @@
+package versionfile
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+var (
+	// tomlTable matches a table header; group 1 is [[ for an array of
+	// tables.
+	tomlTable = regexp.MustCompile(`^\s*(\[\[?)\s*([^\[\]]+?)\s*\]\]?\s*(?:#.*)?$`)
+	// tomlString matches a key assigned a basic or literal string on one
+	// line; group 2 is the value including its quotes.
+	tomlString = regexp.MustCompile(`^\s*([A-Za-z0-9_\-."' ]+?)\s*=\s*("[^"\\\n]*"|'[^'\n]*')\s*(?:#.*)?$`)
+)
+
+// replaceTOML replaces the string at the dotted key. The file must parse
+// as TOML; the value is then found line by line under its table header so
+// comments and formatting stay as they are. Keys in arrays of tables are
+// not supported.
+func replaceTOML(data []byte, key, newVersion string) ([]byte, string, error) {
+	var document map[string]interface{}
+	if _, err := toml.Decode(string(data), &document); err != nil {
+		return nil, "", err
+	}
+
+	var value interface{} = document
+	for _, part := range strings.Split(key, ".") {
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, "", fmt.Errorf("key %s not found", key)
+		}
+		if value, ok = table[part]; !ok {
+			return nil, "", fmt.Errorf("key %s not found", key)
+		}
+	}
+	old, ok := value.(string)
+	if !ok {
+		return nil, "", fmt.Errorf("%s is not a string", key)
+	}
+
+	table := ""
+	offset := 0
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		content := strings.TrimRight(line, "\r\n")
+		if match := tomlTable.FindStringSubmatch(content); match != nil {
+			table = tomlKey(match[2])
+			if match[1] == "[[" {
+				// Keys in an array of tables never match a dotted key
+				table = "[]"
+			}
+		} else if match := tomlString.FindStringSubmatchIndex(content); match != nil {
+			name := tomlKey(content[match[2]:match[3]])
+			if table != "" {
+				name = table + "." + name
+			}
+			if name == key {
+				quote := content[match[4] : match[4]+1]
+				return splice(data, offset+match[4], offset+match[5], quote+newVersion+quote), old, nil
+			}
+		}
+		offset += len(line)
+	}
+
+	return nil, "", fmt.Errorf("%s must be a string on a single line", key)
+}
+
+// tomlKey normalises a dotted key by removing whitespace and quotes around
+// its parts.
+func tomlKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
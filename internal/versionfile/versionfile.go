@@ -0,0 +1,125 @@
+// Package versionfile replaces the version recorded in files such as
+// package.json, Chart.yaml, Cargo.toml, a VERSION file or a Go constant,
+// leaving the rest of each file byte for byte.
+package versionfile
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatTOML  = "toml"
+	FormatRegex = "regex"
+	FormatPlain = "plain"
+	FormatGo    = "go"
+)
+
+// File describes where a file records the version.
+type File struct {
+	Path string
+	// Format is one of the Format constants. When empty it follows from
+	// the file: regex when Pattern is set, otherwise the extension, with
+	// plain for files such as VERSION.
+	Format string
+	// Key is the dotted path of the version in JSON, YAML and TOML files,
+	// such as version or package.version, or the name of a Go constant or
+	// variable. It defaults to package.version in Cargo.toml,
+	// project.version in pyproject.toml, Version in Go files and version
+	// elsewhere.
+	Key string
+	// Pattern is a regular expression with one capture group that matches
+	// the version. Every match is replaced.
+	Pattern string
+}
+
+// Validate checks the format, key and pattern without reading the file.
+func (f File) Validate() error {
+	switch f.format() {
+	case FormatJSON, FormatYAML, FormatTOML, FormatGo:
+		for _, part := range strings.Split(f.key(), ".") {
+			if part == "" {
+				return fmt.Errorf("invalid key %q", f.key())
+			}
+		}
+	case FormatRegex:
+		re, err := regexp.Compile(f.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		if re.NumSubexp() != 1 {
+			return fmt.Errorf("pattern %q must have exactly one capture group", f.Pattern)
+		}
+	case FormatPlain:
+	default:
+		return fmt.Errorf("unknown format %s (must be %s, %s, %s, %s, %s or %s)",
+			f.Format, FormatJSON, FormatYAML, FormatTOML, FormatRegex, FormatPlain, FormatGo)
+	}
+	return nil
+}
+
+// Replace returns data with the version replaced by newVersion, and the
+// version it replaced.
+func (f File) Replace(data []byte, newVersion string) ([]byte, string, error) {
+	if err := f.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	switch f.format() {
+	case FormatJSON:
+		return replaceJSON(data, f.key(), newVersion)
+	case FormatYAML:
+		return replaceYAML(data, f.key(), newVersion)
+	case FormatTOML:
+		return replaceTOML(data, f.key(), newVersion)
+	case FormatGo:
+		return replaceGo(f.Path, data, f.key(), newVersion)
+	case FormatRegex:
+		return replaceRegex(data, f.Pattern, newVersion)
+	default:
+		return replacePlain(data, newVersion)
+	}
+}
+
+func (f File) format() string {
+	if f.Format != "" {
+		return f.Format
+	}
+	if f.Pattern != "" {
+		return FormatRegex
+	}
+
+	switch strings.ToLower(filepath.Ext(f.Path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	case ".go":
+		return FormatGo
+	default:
+		return FormatPlain
+	}
+}
+
+func (f File) key() string {
+	if f.Key != "" {
+		return f.Key
+	}
+
+	switch {
+	case filepath.Base(f.Path) == "Cargo.toml":
+		return "package.version"
+	case filepath.Base(f.Path) == "pyproject.toml":
+		return "project.version"
+	case f.format() == FormatGo:
+		return "Version"
+	default:
+		return "version"
+	}
+}
@@ -0,0 +1,152 @@
+package versionfile
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+const newVersion = "2.0.0-rc.1"
+
+// TestReplaceGolden replaces the version in each file under testdata and
+// compares the result with its .golden file. Only the version itself may
+// change; everything around it stays byte for byte.
+func TestReplaceGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		file File
+	}{
+		{name: "package.json", file: File{}},
+		{name: "nested.json", file: File{Key: "app.meta.version"}},
+		{name: "Chart.yaml", file: File{}},
+		{name: "Chart.yaml", file: File{Key: "appVersion"}},
+		{name: "values.yml", file: File{Key: "image.tag"}},
+		{name: "Cargo.toml", file: File{}},
+		{name: "pyproject.toml", file: File{}},
+		{name: "pyproject.toml", file: File{Key: "tool.bumpversion.current_version"}},
+		{name: "version.go", file: File{}},
+		{name: "version.go", file: File{Key: "Release"}},
+		{name: "Dockerfile", file: File{Pattern: `image\.version="([^"]+)"`}},
+		{name: "VERSION", file: File{}},
+	}
+
+	for _, tt := range tests {
+		golden := tt.name
+		if tt.file.Key != "" {
+			golden += "." + tt.file.Key
+		}
+		if tt.file.Pattern != "" {
+			golden += ".regex"
+		}
+
+		t.Run(golden, func(t *testing.T) {
+			path := filepath.Join("testdata", tt.name)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.file.Path = path
+			got, old, err := tt.file.Replace(data, newVersion)
+			if err != nil {
+				t.Fatalf("Replace failed: %v", err)
+			}
+			if old != "1.4.2" {
+				t.Errorf("Replace returned the old version %q, want 1.4.2", old)
+			}
+			if !onlyVersionChanged(data, got, old, newVersion) {
+				t.Errorf("Replace changed more than the version:\n%s", got)
+			}
+
+			goldenPath := filepath.Join("testdata", golden+".golden")
+			if *update {
+				if err := os.WriteFile(goldenPath, got, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Replace =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestReplaceRegexEveryMatch(t *testing.T) {
+	data := []byte("a: 1.4.2\nb: 1.4.2\nc: 1.4.2-old\n")
+	f := File{Path: "notes.txt", Pattern: `: (\d+\.\d+\.\d+)\n`}
+
+	got, old, err := f.Replace(data, newVersion)
+	if err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	want := "a: 2.0.0-rc.1\nb: 2.0.0-rc.1\nc: 1.4.2-old\n"
+	if string(got) != want || old != "1.4.2" {
+		t.Errorf("Replace = %q, %q, want %q, 1.4.2", got, old, want)
+	}
+}
+
+func TestReplaceErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    File
+		data    string
+		wantErr string
+	}{
+		{name: "json missing key", file: File{Path: "package.json"}, data: `{"name": "web", "engines": {"version": "1.0.0"}}`, wantErr: "key version not found"},
+		{name: "json missing nested key", file: File{Path: "package.json", Key: "app.version"}, data: `{"app": {"name": "web"}, "version": "1.0.0"}`, wantErr: "key app.version not found"},
+		{name: "json number", file: File{Path: "package.json"}, data: `{"version": 1}`, wantErr: "version is not a string"},
+		{name: "json object", file: File{Path: "package.json"}, data: `{"version": {"major": 1}}`, wantErr: "key version not found"},
+		{name: "json array on the path", file: File{Path: "package.json", Key: "app.version"}, data: `{"app": [{"version": "1.0.0"}]}`, wantErr: "key app.version not found"},
+		{name: "yaml missing key", file: File{Path: "Chart.yaml"}, data: "name: web\nappVersion: 1.0.0\n", wantErr: "key version not found"},
+		{name: "yaml missing nested key", file: File{Path: "values.yaml", Key: "image.tag"}, data: "image:\n  repository: web\n", wantErr: "key image.tag not found"},
+		{name: "yaml mapping", file: File{Path: "Chart.yaml"}, data: "version:\n  major: 1\n", wantErr: "version is not a scalar"},
+		{name: "yaml block scalar", file: File{Path: "Chart.yaml"}, data: "version: |\n  1.0.0\n", wantErr: "must be a plain or quoted scalar"},
+		{name: "toml missing key", file: File{Path: "Cargo.toml"}, data: "[package]\nname = \"web\"\n", wantErr: "key package.version not found"},
+		{name: "toml integer", file: File{Path: "Cargo.toml"}, data: "[package]\nversion = 1\n", wantErr: "package.version is not a string"},
+		{name: "toml multi-line string", file: File{Path: "Cargo.toml"}, data: "[package]\nversion = \"\"\"1.0.0\"\"\"\n", wantErr: "must be a string on a single line"},
+		{name: "go missing constant", file: File{Path: "version.go"}, data: "package build\n\nconst Name = \"web\"\n", wantErr: "no constant or variable Version"},
+		{name: "go integer", file: File{Path: "version.go"}, data: "package build\n\nconst Version = 1\n", wantErr: "Version is not a string literal"},
+		{name: "go without value", file: File{Path: "version.go"}, data: "package build\n\nvar Version string\n", wantErr: "Version has no value"},
+		{name: "regex without match", file: File{Path: "Dockerfile", Pattern: `VERSION=(\S+)`}, data: "FROM alpine\n", wantErr: "does not match"},
+		{name: "regex without group", file: File{Path: "Dockerfile", Pattern: `VERSION=\S+`}, data: "VERSION=1.0.0\n", wantErr: "exactly one capture group"},
+		{name: "unknown format", file: File{Path: "VERSION", Format: "ini"}, data: "1.0.0\n", wantErr: "unknown format ini"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := tt.file.Replace([]byte(tt.data), newVersion)
+			if err == nil {
+				t.Fatalf("Replace = %q, want an error", got)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Replace failed with %q, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// onlyVersionChanged reports whether got is data with one occurrence of old
+// replaced by newVersion.
+func onlyVersionChanged(data, got []byte, old, newVersion string) bool {
+	if len(got) != len(data)-len(old)+len(newVersion) {
+		return false
+	}
+	for i := 0; i+len(old) <= len(data); i++ {
+		if bytes.Equal(data[:i], got[:i]) &&
+			string(data[i:i+len(old)]) == old &&
+			string(got[i:i+len(newVersion)]) == newVersion &&
+			bytes.Equal(data[i+len(old):], got[i+len(newVersion):]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package versionfile
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// replaceYAML replaces the scalar at the dotted key, located by its
+// position in the source so comments and formatting stay as they are.
+func replaceYAML(data []byte, key, newVersion string) ([]byte, string, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, "", err
+	}
+	if len(document.Content) == 0 {
+		return nil, "", fmt.Errorf("key %s not found", key)
+	}
+
+	node := document.Content[0]
+	for _, part := range strings.Split(key, ".") {
+		if node.Kind != yaml.MappingNode {
+			return nil, "", fmt.Errorf("key %s not found", key)
+		}
+		var value *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == part {
+				value = node.Content[i+1]
+				break
+			}
+		}
+		if value == nil {
+			return nil, "", fmt.Errorf("key %s not found", key)
+		}
+		node = value
+	}
+	if node.Kind != yaml.ScalarNode {
+		return nil, "", fmt.Errorf("%s is not a scalar", key)
+	}
+
+	var quote string
+	switch node.Style {
+	case 0:
+	case yaml.DoubleQuotedStyle:
+		quote = `"`
+	case yaml.SingleQuotedStyle:
+		quote = `'`
+	default:
+		return nil, "", fmt.Errorf("%s must be a plain or quoted scalar", key)
+	}
+
+	start, ok := yamlOffset(data, node.Line, node.Column)
+	raw := quote + node.Value + quote
+	if !ok || !bytes.HasPrefix(data[start:], []byte(raw)) {
+		return nil, "", fmt.Errorf("cannot locate %s in the file", key)
+	}
+
+	return splice(data, start, start+len(raw), quote+newVersion+quote), node.Value, nil
+}
+
+// yamlOffset converts a 1-based line and character column to a byte offset.
+func yamlOffset(data []byte, line, column int) (int, bool) {
+	offset := 0
+	for ; line > 1; line-- {
+		i := bytes.IndexByte(data[offset:], '\n')
+		if i < 0 {
+			return 0, false
+		}
+		offset += i + 1
+	}
+	for ; column > 1; column-- {
+		if offset >= len(data) || data[offset] == '\n' {
+			return 0, false
+		}
+		_, size := utf8.DecodeRune(data[offset:])
+		offset += size
+	}
+	return offset, true
+}